@@ -0,0 +1,298 @@
+package hypeql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Definition of an argument of the arguments struct (field with the "arg" tag)
+type argumentDefinition struct {
+	name       string       // Name of the argument in the query
+	index      []int        // Index of the struct field (for fields of embedded structs it's a sequence)
+	fieldType  reflect.Type // Type of the struct field
+	required   bool         // Fields that aren't pointers and have no "default" tag are required
+	defaultVal interface{}  // Value of the "default" tag converted to the field's type (nil if the field has no default value)
+	rules      argumentRules
+}
+
+// Definitions of arguments structs read without custom scalars (reading tags and compiling patterns on every call of a resolver function is expensive).
+// Definitions read with custom scalars are cached by their registry (see "ScalarRegistry")
+var defaultArgumentDefinitions sync.Map
+
+// Returns true if the type is the arguments struct (or pointer to it) that a list resolver can receive instead of map[string]interface{}
+func isArgumentsType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
+
+// Reads definitions of arguments from the "arg" and "default" tags (and validation rules) of the struct
+func argumentDefinitions(t reflect.Type, scalars *ScalarRegistry) ([]argumentDefinition, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	cache := &defaultArgumentDefinitions
+	if scalars != nil {
+		cache = &scalars.definitions
+	}
+
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]argumentDefinition), nil
+	}
+
+	// Setup errors aren't cached, so they are gone after registering the missing scalar
+	definitions, err := readArgumentDefinitions(t, scalars)
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(t, definitions)
+
+	return definitions, nil
+}
+
+func readArgumentDefinitions(t reflect.Type, scalars *ScalarRegistry) ([]argumentDefinition, error) {
+	definitions := []argumentDefinition{}
+
+	// Arguments of embedded structs are arguments of the struct too
+	for _, sf := range reflect.VisibleFields(t) {
+		name := sf.Tag.Get("arg")
+		if name == "" {
+			continue
+		}
+
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("%s.%s: argument field must be exported", t.Name(), sf.Name)
+		}
+
+		fieldType := sf.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if !isArgumentKind(fieldType.Kind()) && !scalars.parsable(fieldType) {
+			return nil, fmt.Errorf("%s.%s: unsupported argument type %s", t.Name(), sf.Name, sf.Type.String())
+		}
+
+		definition := argumentDefinition{
+			name:      name,
+			index:     sf.Index,
+			fieldType: sf.Type,
+			required:  sf.Type.Kind() != reflect.Pointer,
+		}
+
+		if defaultTag, ok := sf.Tag.Lookup("default"); ok {
+			converted, err := convertArgument(parseArgumentValue(defaultTag), fieldType, scalars)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: invalid default value: %s", t.Name(), sf.Name, err.Error())
+			}
+
+			definition.required = false
+			definition.defaultVal = converted.Interface()
+		}
+
+		rules, err := parseArgumentRules(sf, fieldType, scalars)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", t.Name(), sf.Name, err.Error())
+		}
+		definition.rules = rules
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+// Parses a value of the tag the same way as values in the query (integers or strings)
+func parseArgumentValue(value string) interface{} {
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+
+	return value
+}
+
+func isArgumentKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// Decodes arguments of the query into the new arguments struct (argsType can be struct or pointer to struct)
+func bindArguments(arguments map[string]interface{}, argsType reflect.Type, path []interface{}, scalars *ScalarRegistry) (reflect.Value, error) {
+	definitions, err := argumentDefinitions(argsType, scalars)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	structType := argsType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	args := reflect.New(structType)
+
+	// Invalid, missing, unknown arguments and violated validation rules are reported together
+	validationErr := &ValidationError{}
+	violate := func(name string, message string) {
+		validationErr.Violations = append(validationErr.Violations, ArgumentViolation{
+			Argument: pathString(appendPath(path, name)),
+			Message:  message,
+		})
+	}
+
+	known := map[string]bool{}
+	for _, definition := range definitions {
+		known[definition.name] = true
+
+		fieldType := definition.fieldType
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		var converted reflect.Value
+		if value, ok := arguments[definition.name]; ok {
+			if converted, err = convertArgument(value, fieldType, scalars); err != nil {
+				violate(definition.name, err.Error())
+				continue
+			}
+		} else if definition.required {
+			violate(definition.name, "is required")
+			continue
+		} else if definition.defaultVal != nil {
+			converted = reflect.ValueOf(definition.defaultVal)
+		}
+
+		field := args.Elem().FieldByIndex(definition.index)
+		if converted.IsValid() {
+			if definition.fieldType.Kind() == reflect.Pointer {
+				pointer := reflect.New(fieldType)
+				pointer.Elem().Set(converted)
+				converted = pointer
+			}
+			field.Set(converted)
+		}
+
+		for _, message := range definition.rules.check(field) {
+			violate(definition.name, message)
+		}
+	}
+
+	// Sorting names to report unknown arguments in the same order every time
+	names := []string{}
+	for name := range arguments {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !known[name] {
+			violate(name, "is unknown")
+		}
+	}
+
+	if len(validationErr.Violations) != 0 {
+		return reflect.Value{}, validationErr
+	}
+
+	if argsType.Kind() == reflect.Pointer {
+		return args, nil
+	}
+
+	return args.Elem(), nil
+}
+
+// Converts the argument's value (int or string from the query) to the type.
+// The query parser reads unquoted values that aren't integers as strings, so bools and floats can be passed as strings
+func convertArgument(value interface{}, t reflect.Type, scalars *ScalarRegistry) (reflect.Value, error) {
+	// Custom scalar types
+	if !isArgumentKind(t.Kind()) || scalars.lookup(t) != nil {
+		if !scalars.parsable(t) {
+			return reflect.Value{}, fmt.Errorf("has unsupported type %s", t.String())
+		}
+
+		return scalars.parse(value, t)
+	}
+
+	result := reflect.New(t).Elem()
+	mistyped := fmt.Errorf("must be %s, but it's %T", t.Kind().String(), value)
+
+	switch t.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return reflect.Value{}, mistyped
+		}
+		result.SetString(s)
+
+	case reflect.Bool:
+		var b bool
+		switch v := value.(type) {
+		case bool:
+			b = v
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return reflect.Value{}, mistyped
+			}
+			b = parsed
+		default:
+			return reflect.Value{}, mistyped
+		}
+		result.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := value.(int)
+		if !ok {
+			return reflect.Value{}, mistyped
+		}
+		if result.OverflowInt(int64(i)) {
+			return reflect.Value{}, fmt.Errorf("overflows %s", t.Kind().String())
+		}
+		result.SetInt(int64(i))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := value.(int)
+		if !ok {
+			return reflect.Value{}, mistyped
+		}
+		if i < 0 || result.OverflowUint(uint64(i)) {
+			return reflect.Value{}, fmt.Errorf("overflows %s", t.Kind().String())
+		}
+		result.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch v := value.(type) {
+		case int:
+			f = float64(v)
+		case float64:
+			f = v
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+				return reflect.Value{}, mistyped
+			}
+			f = parsed
+		default:
+			return reflect.Value{}, mistyped
+		}
+		if result.OverflowFloat(f) {
+			return reflect.Value{}, fmt.Errorf("overflows %s", t.Kind().String())
+		}
+		result.SetFloat(f)
+	}
+
+	return result, nil
+}
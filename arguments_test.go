@@ -0,0 +1,87 @@
+package hypeql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type Store struct {
+	Goods []Good `json:"goods" fun:"Rgoods"`
+}
+
+type GoodsArgs struct {
+	Category string  `arg:"category"`
+	Page     uint    `arg:"page" default:"1"`
+	Limit    *int    `arg:"limit"`
+	MaxPrice float64 `arg:"maxPrice" default:"99.5"`
+	InStock  bool    `arg:"inStock" default:"true"`
+	Ignored  string  // Field without the "arg" tag isn't an argument
+	Note     *string `arg:"note"`
+}
+
+func (a Store) Rgoods(ctx *map[string]any, args GoodsArgs) []Good {
+	limit := "nil"
+	if args.Limit != nil {
+		limit = fmt.Sprint(*args.Limit)
+	}
+
+	return []Good{{Name: fmt.Sprint(args.Category, " ", args.Page, " ", limit, " ", args.MaxPrice, " ", args.InStock, " ", args.Note == nil)}}
+}
+
+type Good struct {
+	Name string `json:"name"`
+}
+
+func TestArgumentsBinding(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+	parser := NewQueryParser(QueryParserConfig{})
+
+	process := func(query string) (string, error) {
+		body, err := parser.Parse(query)
+		if err != nil {
+			t.Fatal("Parse error: " + err.Error())
+		}
+
+		return generator.Generate(body, Store{}, map[string]any{})
+	}
+
+	resp, err := process(`{goods(category: "food"){name}}`)
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"goods":[{"name":"food 1 nil 99.5 true true"}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	resp, err = process(`{goods(category: "toys", page: 3, limit: 10, maxPrice: 20, inStock: false, note: "hi"){name}}`)
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"goods":[{"name":"toys 3 10 20 false false"}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	errorsMustBe := map[string]string{
+		`{goods{name}}`: "goods.category is required",
+		`{goods(category: "a", color: "red"){name}}`: "goods.color is unknown",
+		`{goods(category: 1){name}}`:                 "goods.category must be string, but it's int",
+		`{goods(category: "a", page: -1){name}}`:     "goods.page overflows uint",
+		`{goods(category: "a", inStock: 5){name}}`:   "goods.inStock must be bool, but it's int",
+		`{goods(page: "%d", size: 1){name}}`:         "goods.category is required; goods.page must be uint, but it's string; goods.size is unknown",
+	}
+
+	for query, mustBe := range errorsMustBe {
+		_, err := process(query)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || err.Error() != mustBe {
+			t.Fatal("Not equal: ", err)
+		}
+	}
+
+	if err := generator.Validate(Store{}); err != nil {
+		t.Fatal("Validation error: " + err.Error())
+	}
+}
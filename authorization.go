@@ -0,0 +1,110 @@
+package hypeql
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Field with the "auth" tag that is checked by the authorizer before its value is received
+type AuthRequest struct {
+	Path         []interface{} // Field names and list indexes from the root to the field
+	Field        string        // Field's tag name
+	Roles        []string      // Roles listed in the "auth" tag of the field
+	RequestRoles []string      // Roles of the request (see "RolesKey" and "RolesContext")
+	Object       interface{}   // Object (struct) that has the field
+	Context      interface{}   // Context that resolver functions receive (*map[string]interface{} or *C of the typed generator)
+}
+
+// Decides if the field can be received, returns nil if it can
+type Authorizer interface {
+	Authorize(req AuthRequest) error
+}
+
+// Function that implements the Authorizer interface
+type AuthorizerFunc func(req AuthRequest) error
+
+func (a AuthorizerFunc) Authorize(req AuthRequest) error {
+	return a(req)
+}
+
+// Default authorizer: the field can be received if the request has at least one of roles listed in the "auth" tag
+type RolesAuthorizer struct{}
+
+func (a RolesAuthorizer) Authorize(req AuthRequest) error {
+	for _, role := range req.Roles {
+		if slices.Contains(req.RequestRoles, role) {
+			return nil
+		}
+	}
+
+	return &PermissionError{
+		Path: pathString(req.Path),
+	}
+}
+
+// Error of the field that the request doesn't have permission to receive (the response error has the path of the field)
+type PermissionError struct {
+	Path string
+}
+
+func (a *PermissionError) Error() string {
+	return "permission denied"
+}
+
+func (a *PermissionError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": "FORBIDDEN",
+	}
+}
+
+// Key of the context map that keeps roles of the request ([]string).
+// Fields with the "auth" tag (`auth:"admin,editor"`) are received only if the request has one of listed roles
+const RolesKey = "hypeql.roles"
+
+// Context of the typed generator that has roles of the request
+type RolesContext interface {
+	RequestRoles() []string
+}
+
+// Returns roles of the request from the context
+func requestRoles(ctx interface{}) []string {
+	switch ctx := ctx.(type) {
+	case *map[string]interface{}:
+		roles, _ := (*ctx)[RolesKey].([]string)
+		return roles
+	case RolesContext:
+		return ctx.RequestRoles()
+	}
+
+	return nil
+}
+
+// Checks permission of the field that has the "auth" tag (fields without the tag are always allowed)
+func (a responseGenerator) authorize(sf reflect.StructField, ctx interface{}, path []interface{}, object interface{}) error {
+	tag, ok := sf.Tag.Lookup("auth")
+	if !ok {
+		return nil
+	}
+
+	roles := []string{}
+	for _, role := range strings.Split(tag, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	var authorizer Authorizer = RolesAuthorizer{}
+	if a.Config.Authorizer != nil {
+		authorizer = a.Config.Authorizer
+	}
+
+	return authorizer.Authorize(AuthRequest{
+		Path:         path,
+		Field:        sf.Tag.Get("json"),
+		Roles:        roles,
+		RequestRoles: requestRoles(ctx),
+		Object:       object,
+		Context:      ctx,
+	})
+}
@@ -0,0 +1,121 @@
+package hypeql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type Staff struct {
+	Employees []Employee `json:"employees"`
+}
+
+type Employee struct {
+	Name   string `json:"name"`
+	Salary int    `json:"salary" fun:"Rsalary" auth:"admin, accountant"`
+}
+
+func (a Employee) Rsalary(ctx *map[string]any) int {
+	(*ctx)["salaryCalled"] = true
+	return 1000
+}
+
+func TestAuthorization(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	parsed, err := parser.Parse("{employees{name,salary}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	staff := Staff{
+		Employees: []Employee{{Name: "Bob"}},
+	}
+
+	resp, err := generator.Generate(parsed, staff, map[string]any{RolesKey: []string{"accountant"}})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"employees":[{"name":"Bob","salary":1000}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	// Resolver function is not called without permission
+	ctx := map[string]any{RolesKey: []string{"user"}}
+	_, err = generator.Generate(parsed, staff, ctx)
+	var permissionErr *PermissionError
+	if !errors.As(err, &permissionErr) || err.Error() != "permission denied" || permissionErr.Path != "employees.0.salary" || ctx["salaryCalled"] != nil {
+		t.Fatal("Not equal")
+	}
+
+	resp, err = NewResponseGenerator(ResponseGeneratorConfig{PartialResults: true}).Generate(parsed, staff, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	mustBe := `{"data":{"employees":[{"name":"Bob","salary":null}]},"errors":[{"message":"permission denied","path":["employees",0,"salary"],"extensions":{"code":"FORBIDDEN"}}]}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+}
+
+type Payroll struct {
+	Total int `json:"total" auth:"admin"`
+}
+
+type Clerk struct {
+	Roles []string
+}
+
+func (a Clerk) RequestRoles() []string {
+	return a.Roles
+}
+
+func TestTypedAuthorization(t *testing.T) {
+	generator, err := NewTypedResponseGenerator[Clerk](ResponseGeneratorConfig{}, Payroll{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	resp, err := generator.Generate([]any{"total"}, Payroll{Total: 5000}, &Clerk{Roles: []string{"admin"}})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"total":5000}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	if _, err := generator.Generate([]any{"total"}, Payroll{Total: 5000}, &Clerk{}); err == nil || err.Error() != "permission denied" {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestCustomAuthorizer(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Authorizer: AuthorizerFunc(func(req AuthRequest) error {
+			ctx := req.Context.(*map[string]any)
+			if (*ctx)["user"] == req.Object.(Employee).Name {
+				return nil
+			}
+
+			return fmt.Errorf("only own salary")
+		}),
+	})
+
+	staff := Staff{
+		Employees: []Employee{{Name: "Bob"}},
+	}
+	query := []any{[]any{"employees", []any{"salary"}}}
+
+	if _, err := generator.Generate(query, staff, map[string]any{"user": "Bob"}); err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if _, err := generator.Generate(query, staff, map[string]any{"user": "Alice"}); err == nil || err.Error() != "only own salary" {
+		t.Fatal("Not equal")
+	}
+}
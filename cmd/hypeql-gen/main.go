@@ -0,0 +1,439 @@
+// Command hypeql-gen generates executors of response structs: switches over tags of selected fields that read fields and call resolver functions directly
+// and pass objects of list fields to executors of their types, so generators process these structs without reflection.
+// It's a separate module, so the library doesn't depend on golang.org/x/tools.
+// Add the directive to the package with response structs and run "go generate":
+//
+//	//go:generate go run github.com/dadencukillia/hypeql/cmd/hypeql-gen@latest -type Response,Film
+//
+// Types that aren't listed (or all struct types if the list is empty) are processed by reflection, fields and resolver functions that can't be generated too.
+// Set the "IgnoreGenerated" option of the generator to use reflection for all types
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const hypeqlPath = "github.com/dadencukillia/hypeql"
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated names of struct types (all struct types with JSON tags if empty)")
+	output := flag.String("output", "hypeql_gen.go", "name of the generated file in the package directory")
+	flag.Parse()
+
+	pattern := "."
+	if flag.NArg() > 0 {
+		pattern = flag.Arg(0)
+	}
+
+	names := []string{}
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	pkg, err := loadPackage("", pattern, *output)
+	if err != nil {
+		fail(err)
+	}
+
+	code, err := generate(pkg, names)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(filepath.Dir(pkg.GoFiles[0]), *output), code, 0o644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "hypeql-gen: "+err.Error())
+	os.Exit(1)
+}
+
+// Loads the package (the pattern is relative to the directory, stay it empty to use the current one), errors of the previously generated file are ignored (it's replaced)
+func loadPackage(dir string, pattern string, output string) (*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		// Types are checked from sources, so export data of any Go version is not read
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes,
+		Dir:  dir,
+	}, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("pattern %s must match one package, but it matches %d", pattern, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	for _, pkgErr := range pkg.Errors {
+		if !strings.Contains(pkgErr.Pos, output+":") {
+			return nil, pkgErr
+		}
+	}
+
+	if len(pkg.GoFiles) == 0 {
+		return nil, fmt.Errorf("package %s doesn't have Go files", pkg.PkgPath)
+	}
+
+	return pkg, nil
+}
+
+// Generated source of the package
+type generator struct {
+	pkg       *types.Package
+	imports   map[string]string     // Names of imported packages by paths
+	generated map[*types.Named]bool // Types whose executors are generated
+	body      bytes.Buffer
+}
+
+// Returns the source of the file with executors of the struct types
+func generate(pkg *packages.Package, names []string) ([]byte, error) {
+	g := &generator{
+		pkg:       pkg.Types,
+		imports:   map[string]string{hypeqlPath: "hypeql"},
+		generated: map[*types.Named]bool{},
+	}
+
+	if len(names) == 0 {
+		for _, name := range pkg.Types.Scope().Names() {
+			if _, ok := g.structType(name); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	// Executors of other types are passed as types of children, so types are checked before writing executors
+	generated := []*types.Named{}
+	resolves := map[*types.Named]string{}
+	for _, name := range names {
+		named, ok := g.structType(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("%s isn't a struct type of the package %s", name, pkg.PkgPath)
+		}
+
+		resolve, err := g.resolveFunc(named)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "hypeql-gen: "+err.Error()+", the type is processed by reflection")
+			continue
+		}
+
+		generated = append(generated, named)
+		resolves[named] = resolve
+		g.generated[named] = true
+	}
+
+	g.printf("var (\n")
+	for _, named := range generated {
+		g.printf("%s *hypeql.GeneratedType\n", executorName(named))
+	}
+	g.printf(")\n\n")
+
+	g.printf("func init() {\n")
+	for _, named := range generated {
+		resolve := "nil"
+		if resolves[named] != "" {
+			resolve = "hypeqlResolve" + named.Obj().Name()
+		}
+
+		g.printf("%s = hypeql.RegisterGenerated[%s]([]string{%s}, hypeqlExecute%s, %s)\n", executorName(named), named.Obj().Name(), strings.Join(g.keys(named), ", "), named.Obj().Name(), resolve)
+	}
+	g.printf("}\n")
+
+	for _, named := range generated {
+		g.generateType(named)
+
+		if resolves[named] != "" {
+			g.printf("\n%s\n", resolves[named])
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by hypeql-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg.Types.Name())
+
+	paths := []string{}
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		if name := g.imports[path]; name != filepath.Base(path) {
+			fmt.Fprintf(&out, "\t%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(g.body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+func (a *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&a.body, format, args...)
+}
+
+// Returns the name of the variable with the executor of the type
+func executorName(named *types.Named) string {
+	return "hypeql" + named.Obj().Name()
+}
+
+// Returns the non-generic struct type declared in the package with fields that have JSON tags
+func (a *generator) structType(name string) (*types.Named, bool) {
+	typeName, ok := a.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok || typeName.IsAlias() {
+		return nil, false
+	}
+
+	named, ok := typeName.Type().(*types.Named)
+	if !ok || named.TypeParams().Len() != 0 {
+		return nil, false
+	}
+
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if reflect.StructTag(st.Tag(i)).Get("json") != "" {
+			return named, true
+		}
+	}
+
+	return nil, false
+}
+
+// Generated field of the struct
+type field struct {
+	key      string
+	name     string
+	resolver string // Function that calls the resolver function (empty if the struct doesn't have it)
+	children string // Executor of objects of the list field ("nil" if their type isn't generated)
+}
+
+// Returns fields of the struct type that are generated, others are processed by reflection
+func (a *generator) fields(named *types.Named) []field {
+	st := named.Underlying().(*types.Struct)
+	methods := types.NewMethodSet(types.NewPointer(named))
+
+	// Fields with the same tag are processed by reflection (they are found by their kinds)
+	tags := map[string]int{}
+	for i := 0; i < st.NumFields(); i++ {
+		tags[reflect.StructTag(st.Tag(i)).Get("json")]++
+	}
+
+	fields := []field{}
+	for i := 0; i < st.NumFields(); i++ {
+		structField := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		key := tag.Get("json")
+
+		if key == "" || tags[key] > 1 || !structField.Exported() {
+			continue
+		}
+
+		if _, ok := structField.Type().Underlying().(*types.Signature); ok {
+			continue
+		}
+
+		f := field{
+			key:      key,
+			name:     structField.Name(),
+			children: a.children(structField.Type()),
+		}
+
+		if funcName := tag.Get("fun"); funcName != "" {
+			if method := lookupMethod(methods, funcName); method != nil {
+				code, ok := a.resolverCall(method)
+				if !ok {
+					continue
+				}
+
+				f.resolver = code
+			}
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// Returns quoted tags of generated fields of the struct type
+func (a *generator) keys(named *types.Named) []string {
+	keys := []string{}
+	for _, f := range a.fields(named) {
+		keys = append(keys, strconv.Quote(f.key))
+	}
+
+	return keys
+}
+
+// Returns the executor of objects of the list field's type (a slice of the struct, the struct or the pointer to it)
+func (a *generator) children(t types.Type) string {
+	if slice, ok := t.(*types.Slice); ok {
+		t = slice.Elem()
+	}
+
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok && a.generated[named] {
+		return executorName(named)
+	}
+
+	return "nil"
+}
+
+// Writes the executor of the struct type: the switch over tags of selected fields
+func (a *generator) generateType(named *types.Named) {
+	name := named.Obj().Name()
+
+	a.printf("\nfunc hypeqlExecute%s(x *hypeql.GeneratedSelection, receiver *%s) (interface{}, error) {\n", name, name)
+	a.printf("switch x.Key() {\n")
+	for i, f := range a.fields(named) {
+		resolver := "nil"
+		if f.resolver != "" {
+			resolver = f.resolver
+		}
+
+		a.printf("case %q:\n", f.key)
+		a.printf("return x.Field(%d, receiver.%s, %s, %s)\n", i, f.name, resolver, f.children)
+	}
+	a.printf("}\n\n")
+	a.printf("return x.Reflect()\n")
+	a.printf("}\n")
+}
+
+// Returns the exported method of the method set (reflection finds exported methods only)
+func lookupMethod(methods *types.MethodSet, name string) *types.Func {
+	selection := methods.Lookup(nil, name)
+	if selection == nil || !selection.Obj().Exported() {
+		return nil
+	}
+
+	return selection.Obj().(*types.Func)
+}
+
+// Returns the function that calls the "Resolve" method: (ctx, fields []string) returning nothing or error (empty if the struct doesn't have it)
+func (a *generator) resolveFunc(named *types.Named) (string, error) {
+	name := named.Obj().Name()
+
+	method := lookupMethod(types.NewMethodSet(types.NewPointer(named)), "Resolve")
+	if method == nil {
+		return "", nil
+	}
+
+	sig := method.Type().(*types.Signature)
+	if sig.Variadic() || sig.Params().Len() != 2 || !types.Identical(sig.Params().At(1).Type(), types.NewSlice(types.Typ[types.String])) {
+		return "", fmt.Errorf("Resolve method of %s has unsupported signature", name)
+	}
+
+	call := fmt.Sprintf("receiver.Resolve(ctx.(%s), fields)", types.TypeString(sig.Params().At(0).Type(), a.qualifier))
+	header := fmt.Sprintf("func hypeqlResolve%s(receiver *%s, ctx interface{}, fields []string) error {\n", name, name)
+
+	switch {
+	case sig.Results().Len() == 0:
+		return header + call + "\nreturn nil\n}", nil
+	case sig.Results().Len() == 1 && isError(sig.Results().At(0).Type()):
+		return header + "return " + call + "\n}", nil
+	}
+
+	return "", fmt.Errorf("Resolve method of %s has unsupported signature", name)
+}
+
+// Returns the function that calls the resolver function: (ctx) or (ctx, arguments) returning nothing, value, error or value and error
+func (a *generator) resolverCall(method *types.Func) (string, bool) {
+	sig := method.Type().(*types.Signature)
+	if sig.Variadic() || sig.Params().Len() < 1 || sig.Params().Len() > 2 {
+		return "", false
+	}
+
+	args := []string{fmt.Sprintf("ctx.(%s)", types.TypeString(sig.Params().At(0).Type(), a.qualifier))}
+	if sig.Params().Len() == 2 {
+		argsType := sig.Params().At(1).Type()
+		typeName := types.TypeString(argsType, a.qualifier)
+
+		switch underlying := argsType.Underlying().(type) {
+		case *types.Struct:
+			args = append(args, fmt.Sprintf("arguments.(%s)", typeName))
+		case *types.Pointer:
+			if _, ok := underlying.Elem().Underlying().(*types.Struct); !ok {
+				return "", false
+			}
+			args = append(args, fmt.Sprintf("arguments.(%s)", typeName))
+		case *types.Map:
+			// Arguments are received as map[string]interface{} and converted to the parameter's type
+			if !types.Identical(underlying, types.NewMap(types.Typ[types.String], types.NewInterfaceType(nil, nil).Complete())) {
+				return "", false
+			}
+			args = append(args, fmt.Sprintf("(%s)(arguments.(map[string]interface{}))", typeName))
+		default:
+			return "", false
+		}
+	}
+
+	call := fmt.Sprintf("receiver.%s(%s)", method.Name(), strings.Join(args, ", "))
+	results := sig.Results()
+
+	body := ""
+	switch {
+	case results.Len() == 0:
+		body = call + "\nreturn nil, nil"
+	case results.Len() == 1 && isError(results.At(0).Type()):
+		body = "return nil, " + call
+	case results.Len() == 1:
+		body = "return " + call + ", nil"
+	case results.Len() == 2 && isError(results.At(1).Type()) && !isError(results.At(0).Type()):
+		body = "value, err := " + call + "\nif err != nil {\nreturn nil, err\n}\nreturn value, nil"
+	default:
+		return "", false
+	}
+
+	return fmt.Sprintf("func(ctx interface{}, arguments interface{}) (interface{}, error) {\n%s\n}", body), true
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// Writes names of other packages and remembers their imports
+func (a *generator) qualifier(pkg *types.Package) string {
+	if pkg == a.pkg {
+		return ""
+	}
+
+	if name, ok := a.imports[pkg.Path()]; ok {
+		return name
+	}
+
+	// Packages with the same names receive aliases
+	used := map[string]bool{}
+	for _, name := range a.imports {
+		used[name] = true
+	}
+
+	name := pkg.Name()
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+
+	a.imports[pkg.Path()] = name
+	return name
+}
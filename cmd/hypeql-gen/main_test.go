@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// testdata/films is the module that uses hypeql of this repository, hypeql_gen.go is generated by the command run in its directory
+func TestGenerate(t *testing.T) {
+	pkg, err := loadPackage("testdata/films", ".", "hypeql_gen.go")
+	if err != nil {
+		t.Fatal("Loading error: " + err.Error())
+	}
+
+	code, err := generate(pkg, []string{})
+	if err != nil {
+		t.Fatal("Generating error: " + err.Error())
+	}
+
+	expected, err := os.ReadFile("testdata/films/hypeql_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bytes.ReplaceAll(expected, []byte("\r\n"), []byte("\n")), code) {
+		t.Fatal("Not equal:\n" + string(code))
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	pkg, err := loadPackage("testdata/films", ".", "hypeql_gen.go")
+	if err != nil {
+		t.Fatal("Loading error: " + err.Error())
+	}
+
+	if _, err := generate(pkg, []string{"Genre"}); err == nil {
+		t.Fatal("Not error")
+	}
+}
@@ -0,0 +1,62 @@
+// Package films is the input of the generator's test
+package films
+
+import (
+	"errors"
+	"time"
+)
+
+type Context struct {
+	User string
+}
+
+type Response struct {
+	Films   []Film     `json:"films" fun:"Rfilms"`
+	Updated time.Time  `json:"updated"`
+	Hidden  string     `json:"-"`
+	Title   string     `json:"title" fun:"Rtitle"`
+	Missing string     `json:"missing" fun:"Rmissing"`
+	Raw     []any      `json:"raw" fun:"Rraw"`
+	private string     `json:"private"`
+	Twice   string     `json:"twice"`
+	Twice2  []Film     `json:"twice"`
+	Func    func() int `json:"func"`
+}
+
+type FilmsArgs struct {
+	Page int `arg:"page" default:"1"`
+}
+
+func (a Response) Rfilms(ctx *Context, args FilmsArgs) ([]Film, error) {
+	if args.Page < 1 {
+		return nil, errors.New("invalid page")
+	}
+
+	return []Film{{Name: "Spider-Man"}}, nil
+}
+
+func (a Response) Rtitle(ctx *Context) string {
+	return "Films of " + ctx.User
+}
+
+// Variadic functions are called by reflection
+func (a Response) Rraw(ctx *Context, args ...any) []any {
+	return nil
+}
+
+type Film struct {
+	Name   string `json:"name"`
+	Rating int    `json:"rating" fun:"Rrating"`
+	Sequel *Film  `json:"sequel"`
+}
+
+func (a *Film) Resolve(ctx *Context, fields []string) error {
+	return nil
+}
+
+func (a *Film) Rrating(ctx *Context, args map[string]any) (int, error) {
+	return len(a.Name), nil
+}
+
+// Not a struct
+type Genre string
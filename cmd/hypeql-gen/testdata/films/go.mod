@@ -0,0 +1,7 @@
+module films
+
+go 1.22.2
+
+require github.com/dadencukillia/hypeql v0.0.0
+
+replace github.com/dadencukillia/hypeql => ../../../..
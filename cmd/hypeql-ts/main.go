@@ -0,0 +1,122 @@
+// Command hypeql-ts writes TypeScript types of the schema and results of query files.
+// The schema is the JSON file returned by hypeql.Introspect (the HTTP handler with the explorer serves it by "GET /api?schema"):
+//
+//	hypeql-ts -schema schema.json -scalar Time=string -output types.ts queries/*.hql
+//
+// Every query file receives the interface of its result named by the file ("films.hql" is FilmsQuery) and the interface of its variables
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+// Values of the repeated flag
+type scalarsFlag map[string]string
+
+func (a scalarsFlag) String() string {
+	return fmt.Sprint(map[string]string(a))
+}
+
+func (a scalarsFlag) Set(value string) error {
+	name, t, ok := strings.Cut(value, "=")
+	if !ok || name == "" || t == "" {
+		return fmt.Errorf("scalar must be written as Name=type")
+	}
+
+	a[name] = t
+	return nil
+}
+
+func main() {
+	scalars := scalarsFlag{}
+	schemaPath := flag.String("schema", "schema.json", "JSON file of the schema")
+	output := flag.String("output", "", "TypeScript file to write (standard output if empty)")
+	flag.Var(scalars, "scalar", "TypeScript type of the custom scalar as Name=type (repeatable)")
+	flag.Parse()
+
+	code, err := run(*schemaPath, flag.Args(), hypeql.TypeScriptConfig{Scalars: scalars})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hypeql-ts: "+err.Error())
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.WriteString(code)
+		return
+	}
+
+	if err := os.WriteFile(*output, []byte(code), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "hypeql-ts: "+err.Error())
+		os.Exit(1)
+	}
+}
+
+// Returns types of the schema and the query files
+func run(schemaPath string, queryPaths []string, config hypeql.TypeScriptConfig) (string, error) {
+	content, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return "", err
+	}
+
+	schema := &hypeql.Schema{}
+	if err := json.Unmarshal(content, schema); err != nil {
+		return "", fmt.Errorf("schema %s must be JSON object returned by hypeql.Introspect: %s", schemaPath, err.Error())
+	}
+
+	generator := hypeql.NewTypeScriptGenerator(config)
+	parser := hypeql.NewQueryParser(hypeql.QueryParserConfig{})
+
+	var builder strings.Builder
+	builder.WriteString("// Code generated by hypeql-ts. DO NOT EDIT.\n\n")
+	builder.WriteString(generator.Types(schema))
+
+	for _, path := range queryPaths {
+		query, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		// Files with Windows line endings are read as usual
+		parsed, err := parser.Parse(strings.ReplaceAll(string(query), "\r\n", "\n"))
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", path, err.Error())
+		}
+
+		code, err := generator.Query(schema, queryName(path), parsed)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", path, err.Error())
+		}
+
+		builder.WriteString("\n" + code)
+	}
+
+	return builder.String(), nil
+}
+
+// Returns the name of the query type by the file name: "top-films.hql" is TopFilmsQuery
+func queryName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	name := ""
+	for _, word := range strings.FieldsFunc(base, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		name += strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Q" + name
+	}
+
+	if !strings.HasSuffix(name, "Query") {
+		name += "Query"
+	}
+
+	return name
+}
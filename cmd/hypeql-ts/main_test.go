@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+// testdata/types.ts is generated by "go run . -schema testdata/schema.json -scalar Time=string -output testdata/types.ts testdata/top-films.hql"
+func TestRun(t *testing.T) {
+	code, err := run("testdata/schema.json", []string{"testdata/top-films.hql"}, hypeql.TypeScriptConfig{Scalars: map[string]string{"Time": "string"}})
+	if err != nil {
+		t.Fatal("Generating error: " + err.Error())
+	}
+
+	expected, err := os.ReadFile("testdata/types.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.ReplaceAll(string(expected), "\r\n", "\n") != code {
+		t.Fatal("Not equal:\n" + code)
+	}
+
+	if _, err := run("testdata/top-films.hql", []string{}, hypeql.TypeScriptConfig{}); err == nil {
+		t.Fatal("Not error")
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	names := map[string]string{
+		"queries/top-films.hql":  "TopFilmsQuery",
+		"film_query.graphql":     "FilmQuery",
+		"2024 releases.hql":      "Q2024ReleasesQuery",
+		"queries/FilmsQuery.hql": "FilmsQuery",
+	}
+
+	for path, expected := range names {
+		if name := queryName(path); name != expected {
+			t.Fatal("Not equal: " + name)
+		}
+	}
+}
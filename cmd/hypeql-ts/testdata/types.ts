@@ -0,0 +1,24 @@
+// Code generated by hypeql-ts. DO NOT EDIT.
+
+export type Time = string;
+
+export interface Response {
+  films: Array<Film> | null;
+  updated: Time;
+}
+
+export interface Film {
+  name: string;
+  rating: number | null;
+}
+
+export interface TopFilmsQuery {
+  films: Array<{
+    name: string;
+  }> | null;
+  updated: Time;
+}
+
+export interface TopFilmsQueryVariables {
+  page?: number;
+}
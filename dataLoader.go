@@ -0,0 +1,117 @@
+package hypeql
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Value that is loaded later.
+// Resolver functions can return it instead of the value, then the generator receives it after all other fields of the response are processed, so loads of many fields are batched together
+type Deferred interface {
+	Value() (interface{}, error)
+}
+
+// Batching loader that collects keys requested by resolvers and loads them with one call of the batch function.
+// Create a new loader for each request (put it into the context map, for example) because loaded values are cached
+type Loader[K comparable, V any] struct {
+	batch func(keys []K) ([]V, error) // Must return values in the same order as keys
+
+	mu     sync.Mutex
+	cache  map[K]*Thunk[V]
+	queue  []K         // Keys that are waiting for the next batch
+	queued []*Thunk[V] // Thunks of waiting keys
+}
+
+// Value of a key that is loaded by the loader
+type Thunk[V any] struct {
+	dispatch func()
+	done     chan struct{} // Closed when the value is loaded
+	value    V
+	err      error
+}
+
+// Creates a loader with the batch function that must return values in the same order as keys
+func NewLoader[K comparable, V any](batch func(keys []K) ([]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch: batch,
+		cache: map[K]*Thunk[V]{},
+	}
+}
+
+// Adds the key to the next batch and returns a thunk of its value (the same thunk is returned for already requested keys).
+// Return the thunk from a resolver function to load the value together with the keys requested by other resolvers
+func (a *Loader[K, V]) Load(key K) *Thunk[V] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if thunk, ok := a.cache[key]; ok {
+		return thunk
+	}
+
+	thunk := &Thunk[V]{
+		dispatch: a.dispatch,
+		done:     make(chan struct{}),
+	}
+
+	a.cache[key] = thunk
+	a.queue = append(a.queue, key)
+	a.queued = append(a.queued, thunk)
+
+	return thunk
+}
+
+// Loads all waiting keys with one call of the batch function
+func (a *Loader[K, V]) dispatch() {
+	a.mu.Lock()
+	keys, thunks := a.queue, a.queued
+	a.queue, a.queued = nil, nil
+	a.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := a.callBatch(keys)
+	if err == nil && len(values) != len(keys) {
+		err = fmt.Errorf("loader batch function returned %d values for %d keys", len(values), len(keys))
+	}
+
+	for i, thunk := range thunks {
+		if err != nil {
+			thunk.err = err
+		} else {
+			thunk.value = values[i]
+		}
+
+		close(thunk.done)
+	}
+}
+
+// Calls the batch function, its panic is converted to the error (otherwise waiting thunks would wait forever)
+func (a *Loader[K, V]) callBatch(keys []K) (values []V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			values, err = nil, fmt.Errorf("loader batch function panicked: %v", r)
+		}
+	}()
+
+	return a.batch(keys)
+}
+
+// Returns the loaded value, loads the waiting keys if the value is not loaded yet
+func (a *Thunk[V]) Get() (V, error) {
+	select {
+	case <-a.done:
+	default:
+		// Key can be already loading by another goroutine, then dispatch doesn't load it again
+		a.dispatch()
+		<-a.done
+	}
+
+	return a.value, a.err
+}
+
+// Implements the Deferred interface
+func (a *Thunk[V]) Value() (interface{}, error) {
+	return a.Get()
+}
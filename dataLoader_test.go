@@ -0,0 +1,113 @@
+package hypeql
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type Library struct {
+	Books []Book `json:"books"`
+}
+
+type Book struct {
+	Id      int      `json:"id"`
+	Reviews []Review `json:"reviews" fun:"Rreviews"`
+}
+
+type Review struct {
+	AuthorId int    `json:"authorId"`
+	Author   string `json:"author" fun:"Rauthor"`
+}
+
+func (a Book) Rreviews(ctx *map[string]any, args map[string]any) any {
+	return (*ctx)["reviews"].(*Loader[int, []Review]).Load(a.Id)
+}
+
+func (a Review) Rauthor(ctx *map[string]any) any {
+	return (*ctx)["authors"].(*Loader[int, string]).Load(a.AuthorId)
+}
+
+func TestLoaderBatching(t *testing.T) {
+	testLoaderBatching(t, NewResponseGenerator(ResponseGeneratorConfig{}))
+	testLoaderBatching(t, NewResponseGenerator(ResponseGeneratorConfig{
+		Parallel: true,
+	}))
+}
+
+func testLoaderBatching(t *testing.T, generator responseGenerator) {
+	parser := NewQueryParser(QueryParserConfig{})
+
+	reviewsBatches := [][]int{}
+	authorsBatches := [][]int{}
+
+	ctx := map[string]any{
+		"reviews": NewLoader(func(keys []int) ([][]Review, error) {
+			reviewsBatches = append(reviewsBatches, sortedKeys(keys))
+
+			values := [][]Review{}
+			for _, key := range keys {
+				values = append(values, []Review{{AuthorId: key}, {AuthorId: key + 1}})
+			}
+
+			return values, nil
+		}),
+		"authors": NewLoader(func(keys []int) ([]string, error) {
+			authorsBatches = append(authorsBatches, sortedKeys(keys))
+
+			values := []string{}
+			for _, key := range keys {
+				values = append(values, fmt.Sprint("Author #", key))
+			}
+
+			return values, nil
+		}),
+	}
+
+	parsed, err := parser.Parse("{books{id,reviews{author}}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(parsed, Library{
+		Books: []Book{{Id: 1}, {Id: 2}, {Id: 3}},
+	}, ctx)
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	mustBe := `{"books":[{"id":1,"reviews":[{"author":"Author #1"},{"author":"Author #2"}]},{"id":2,"reviews":[{"author":"Author #2"},{"author":"Author #3"}]},{"id":3,"reviews":[{"author":"Author #3"},{"author":"Author #4"}]}]}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	// One batch for each level (keys are sorted because the parallel mode requests them in any order), already loaded keys are cached
+	if fmt.Sprint(reviewsBatches) != "[[1 2 3]]" || fmt.Sprint(authorsBatches) != "[[1 2 3 4]]" {
+		t.Fatal("Not equal: ", reviewsBatches, authorsBatches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	loader := NewLoader(func(keys []int) ([]string, error) {
+		return nil, fmt.Errorf("database is unavailable")
+	})
+
+	first := loader.Load(1)
+	second := loader.Load(2)
+
+	if _, err := first.Get(); err == nil || err.Error() != "database is unavailable" {
+		t.Fatal("Not equal")
+	}
+
+	if _, err := second.Get(); err == nil {
+		t.Fatal("No errors")
+	}
+}
+
+func sortedKeys(keys []int) []int {
+	keys = slices.Clone(keys)
+	slices.Sort(keys)
+
+	return keys
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"math/rand/v2"
 	"net/http"
@@ -10,30 +9,37 @@ import (
 	"time"
 
 	"github.com/dadencukillia/hypeql"
+	"github.com/dadencukillia/hypeql/hypeqlhttp"
 )
 
+// Request context passed to all resolvers
+type Context struct {
+	RandSeed int64
+}
+
 // Data struct declaration, you can take all data from database (but here I using random for database simulation)
 type Response struct {
 	Films []Film `json:"films" fun:"Rfilms"`
 }
 
-func (a Response) Rfilms(ctx *map[string]any, args map[string]any) []Film {
-	// Param p = film part a user want get the information about
-	if count, ok := args["p"]; ok {
-		if q, ok := count.(int); ok {
-			return []Film{
-				{
-					Id: q - 1,
-				},
-			}
+// Arguments of the films list
+type FilmsArgs struct {
+	// Param p = film part a user want get the information about (nil if it's not passed)
+	P *int `arg:"p"`
+}
+
+func (a Response) Rfilms(ctx *Context, args FilmsArgs) []Film {
+	if args.P != nil {
+		return []Film{
+			{
+				Id: *args.P - 1,
+			},
 		}
 	}
 
-	var randomin *rand.Rand
 	// Loading random seed from context
-	if e, ok := (*ctx)["randSeed"].(int64); ok {
-		randomin = rand.New(rand.NewPCG(0, uint64(e)))
-	}
+	e := ctx.RandSeed
+	randomin := rand.New(rand.NewPCG(0, uint64(e)))
 
 	films := []Film{}
 	for i := 0; i < randomin.IntN(10)+1; i++ {
@@ -52,12 +58,10 @@ type Film struct {
 	Comments    []Comment `json:"comments" fun:"Rcomments"`
 }
 
-func (a Film) Rname(ctx *map[string]any) string {
-	var randomin *rand.Rand
+func (a Film) Rname(ctx *Context) string {
 	// Loading random seed from context
-	if e, ok := (*ctx)["randSeed"].(int64); ok {
-		randomin = rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+1)))
-	}
+	e := ctx.RandSeed
+	randomin := rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+1)))
 
 	// List of possible part names
 	partSuffixes := []string{
@@ -72,12 +76,10 @@ func (a Film) Rname(ctx *map[string]any) string {
 	return "Spiderman " + fmt.Sprint(a.Id+1) + ". " + partSuffixes[randomin.IntN(len(partSuffixes))]
 }
 
-func (a Film) Rdescription(ctx *map[string]any) string {
-	var randomin *rand.Rand
+func (a Film) Rdescription(ctx *Context) string {
 	// Loading random seed from context
-	if e, ok := (*ctx)["randSeed"].(int64); ok {
-		randomin = rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+2)))
-	}
+	e := ctx.RandSeed
+	randomin := rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+2)))
 
 	// List of possible description texts
 	descs := []string{
@@ -89,18 +91,16 @@ func (a Film) Rdescription(ctx *map[string]any) string {
 	return descs[randomin.IntN(len(descs))]
 }
 
-func (a Film) RreleaseYear(ctx *map[string]any) int {
-	var randomin *rand.Rand
+func (a Film) RreleaseYear(ctx *Context) int {
 	// Loading random seed from context
-	if e, ok := (*ctx)["randSeed"].(int64); ok {
-		randomin = rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+3)))
-	}
+	e := ctx.RandSeed
+	randomin := rand.New(rand.NewPCG(uint64(a.Id+1), uint64(e+3)))
 
 	// Release year is a number that can be from 1990 to 2024
 	return 1990 + randomin.IntN(35)
 }
 
-func (a Film) Rcomments(ctx *map[string]any, args map[string]any) []Comment {
+func (a Film) Rcomments(ctx *Context, args map[string]any) []Comment {
 	// Comments does not changes
 	return []Comment{
 		{
@@ -123,9 +123,13 @@ func main() {
 	parser := hypeql.NewQueryParser(hypeql.QueryParserConfig{
 		MaxDeepRecursion: 3,
 	})
-	generator := hypeql.NewResponseGenerator(hypeql.ResponseGeneratorConfig{
+	// Resolvers signatures are checked here, before serving requests
+	generator, err := hypeql.NewTypedResponseGenerator[Context](hypeql.ResponseGeneratorConfig{
 		MaxDeepRecursion: 3,
-	})
+	}, Response{})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Main page
 	http.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
@@ -143,7 +147,7 @@ fetch("/api", {
 	body: "{films{id,name,description}}" // Response data struct
 }).then(r => r.json()).then(r => {
 	document.body.innerHTML = "<h2>Films:</h2>"
-	for (const film of r.films) {
+	for (const film of r.data.films) {
 		document.body.innerHTML += ` + "`" + `<hr><div><h3>${film.name}</h3><p>${film.description}</p><a href="/p/${film.id+1}">More details</a></div>` + "`" + `
 	}
 });
@@ -160,64 +164,40 @@ fetch("/api", {
 	method: "post",
 	body: "{films(p:"+part+"){name,description,releaseYear,comments{username,text}}}" // Response data struct
 }).then(r => r.json()).then(r => {
-	const p = r.films[0];
+	const p = r.data.films[0];
 	document.body.innerHTML=` + "`" + `<h2>${p.name} (<i>${p.releaseYear}</i>)</h2><p>${p.description}</p><h3>Comments:</h3>` + "`" + `+p.comments.map(e => ` + "`" + `<hr><div><h3>${e.username}</h3><p>${e.text}</p></div>` + "`" + `).join("")
 });
 </script></body>`))
 	})
 
-	// API page
-	http.HandleFunc("POST /api", func(w http.ResponseWriter, r *http.Request) {
-		// Receiving random seed from cookies or creating new seed if not exists or invalid
-		c, err := r.Cookie("randSeed")
-		var seed int64
-		if err != nil {
-			seed = time.Now().Unix()
+	// API page (open it in the browser to explore the API)
+	api := hypeqlhttp.NewHandler(hypeqlhttp.Config[*Context]{
+		Parser:      parser,
+		Generator:   generator,
+		DataStruct:  Response{},
+		MaxBodySize: 4096,
+		Explorer:    true,
+		Context: func(w http.ResponseWriter, r *http.Request) (*Context, error) {
+			// Receiving random seed from cookies or creating new seed if not exists or invalid
+			if c, err := r.Cookie("randSeed"); err == nil {
+				// Converting seed from cookies to number
+				if seed, err := strconv.Atoi(c.Value); err == nil {
+					return &Context{RandSeed: int64(seed)}, nil
+				}
+			}
+
+			seed := time.Now().Unix()
 			// Writing new seed in cookies
 			http.SetCookie(w, &http.Cookie{
 				Name:  "randSeed",
 				Value: fmt.Sprint(seed),
 			})
-		} else {
-			// Converting seed from cookies to number
-			newSeed, err := strconv.Atoi(c.Value)
-			if err != nil {
-				seed = time.Now().Unix()
-				// Writing new seed in cookies
-				http.SetCookie(w, &http.Cookie{
-					Name:  "randSeed",
-					Value: fmt.Sprint(seed),
-				})
-			} else {
-				seed = int64(newSeed)
-			}
-		}
-
-		// Reading request body
-		cont, err := io.ReadAll(r.Body)
-		r.Body.Close()
-		if err != nil {
-			return
-		}
-
-		// Parsing request body
-		i, err := parser.Parse(string(cont))
-		if err != nil {
-			return
-		}
-		// Generating response body
-		out, err := generator.Generate(i, Response{}, map[string]interface{}{
-			"randSeed": seed,
-		})
 
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("Error: " + err.Error()))
-		}
-
-		// Sending response
-		w.Write([]byte(out))
+			return &Context{RandSeed: seed}, nil
+		},
 	})
+	http.Handle("GET /api", api)
+	http.Handle("POST /api", api)
 
 	// Not found page for API
 	http.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,131 @@
+package hypeql
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Parses queries (the parser created by NewQueryParser)
+type Parser interface {
+	Parse(body string) ([]interface{}, error)
+}
+
+// Processes parsed queries (generators created by NewResponseGenerator and NewTypedResponseGenerator).
+// C is the context: map[string]interface{} for the untyped generator and *C for the typed one
+type Generator[C any] interface {
+	GenerateResult(requestBody []interface{}, dataStruct interface{}, ctx C) (*Result, error)
+}
+
+// Query with its variables: {"query": "...", "variables": {...}, "operationName": "..."}
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"` // Accepted for compatibility, the query language doesn't have named operations
+	Extensions    *RequestExtensions     `json:"extensions,omitempty"`    // Hash of the persisted query
+}
+
+// Struct that parses and processes requests one by one or in batches
+type executor[C any] struct {
+	Config ExecutorConfig[C]
+}
+
+type ExecutorConfig[C any] struct {
+	Parser       Parser
+	Generator    Generator[C]
+	DataStruct   interface{} // Instance of the response struct (can be filled if there are not Resolver functions)
+	MaxBatchSize uint64      // Limits count of requests in a batch, stay 0 if unlimited
+	Concurrent   bool        // Execute requests of a batch concurrently
+
+	PersistedQueries       PersistedQueryStore // Queries that clients can send by their hashes, stay nil if not used
+	RegisterQueries        bool                // Save unknown queries sent with their hashes to the store (automatic persisted queries, use it in development)
+	StrictPersistedQueries bool                // Execute only queries of the store (allowlist), other queries are rejected even if they are sent with hashes
+}
+
+func NewExecutor[C any](config ExecutorConfig[C]) executor[C] {
+	return executor[C]{
+		Config: config,
+	}
+}
+
+// Parses the query (or loads the persisted query), applies variables and processes it.
+// Returns an error if the request can't be executed (the query can't be parsed, isn't persisted or variables are invalid), errors of processing are written to the result
+func (a executor[C]) Execute(request Request, ctx C) (*Result, error) {
+	parsedBody, query, err := a.parseRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsedBody, err = ApplyVariables(parsedBody, request.Variables); err != nil {
+		return nil, err
+	}
+
+	result, err := a.Config.Generator.GenerateResult(parsedBody, a.Config.DataStruct, ctx)
+	if err != nil {
+		// The whole query failed (the generator isn't in the partial results mode)
+		return &Result{
+			Errors: []*ResponseError{NewResponseError(err, nil)},
+		}, nil
+	}
+
+	SetErrorLocations(query, result.Errors)
+
+	return result, nil
+}
+
+// Executes requests of the batch and returns their results in the same order.
+// Errors of requests are written to their results, every request receives its own copy of the context (maps and slices are copied, pointers are shared, see "cloneContext").
+// Returns an error only if the batch is empty or too large
+func (a executor[C]) ExecuteBatch(requests []Request, ctx C) ([]*Result, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("batch is empty")
+	}
+
+	if a.Config.MaxBatchSize != 0 && uint64(len(requests)) > a.Config.MaxBatchSize {
+		return nil, fmt.Errorf("batch has %d requests, but the limit is %d", len(requests), a.Config.MaxBatchSize)
+	}
+
+	results := make([]*Result, len(requests))
+	execute := func(i int, ctx C) {
+		result, err := a.Execute(requests[i], ctx)
+		if err != nil {
+			result = &Result{
+				Errors: []*ResponseError{NewResponseError(err, nil)},
+			}
+		}
+
+		results[i] = result
+	}
+
+	if !a.Config.Concurrent {
+		for i := range requests {
+			execute(i, copyContext(ctx))
+		}
+
+		return results, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+
+		requestCtx := copyContext(ctx)
+		go func() {
+			defer wg.Done()
+			execute(i, requestCtx)
+		}()
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// Returns a copy of the initial context (map or pointer to the typed context)
+func copyContext[C any](ctx C) C {
+	if m, ok := any(ctx).(map[string]interface{}); ok {
+		return any(cloneValue(reflect.ValueOf(m), map[clonedKey]reflect.Value{}).Interface()).(C)
+	}
+
+	return cloneContext(ctx).(C)
+}
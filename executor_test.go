@@ -0,0 +1,114 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type Counter struct {
+	Value int `json:"value" fun:"Rvalue"`
+}
+
+// Every call increases the counter of the context, so requests of the batch must not share it
+func (a Counter) Rvalue(ctx *map[string]interface{}) int {
+	count, _ := (*ctx)["count"].(int)
+	(*ctx)["count"] = count + 1
+
+	return count + 1
+}
+
+func TestExecutor(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		executor := NewExecutor(ExecutorConfig[map[string]interface{}]{
+			Parser:       NewQueryParser(QueryParserConfig{}),
+			Generator:    NewResponseGenerator(ResponseGeneratorConfig{}),
+			DataStruct:   Counter{},
+			MaxBatchSize: 4,
+			Concurrent:   concurrent,
+		})
+
+		ctx := map[string]interface{}{"count": 10}
+		results, err := executor.ExecuteBatch([]Request{
+			{Query: "{value}"},
+			{Query: "{value(count: 1{}}"},
+			{Query: "{value(count: $c){}}"},
+			{Query: "{value}"},
+		}, ctx)
+		if err != nil {
+			t.Fatal("Execution error: " + err.Error())
+		}
+
+		out, err := json.Marshal(results)
+		if err != nil {
+			t.Fatal("Encoding error: " + err.Error())
+		}
+
+		if string(out) != `[{"data":{"value":11}},{"data":null,"errors":[{"message":"the curly bracket is not closed"}]},{"data":null,"errors":[{"message":"variable $c is not defined"}]},{"data":{"value":11}}]` {
+			t.Fatal("Not equal: " + string(out))
+		}
+
+		if ctx["count"] != 10 {
+			t.Fatal("Context of the batch was changed")
+		}
+
+		if _, err := executor.ExecuteBatch(make([]Request, 5), ctx); err == nil || err.Error() != "batch has 5 requests, but the limit is 4" {
+			t.Fatal("Batch size isn't limited")
+		}
+
+		if _, err := executor.ExecuteBatch(nil, ctx); err == nil {
+			t.Fatal("Empty batch is accepted")
+		}
+	}
+
+	executor := NewExecutor(ExecutorConfig[map[string]interface{}]{
+		Parser:    NewQueryParser(QueryParserConfig{}),
+		Generator: NewResponseGenerator(ResponseGeneratorConfig{}),
+	})
+
+	if _, err := executor.Execute(Request{}, nil); err == nil || err.Error() != "query is required" {
+		t.Fatal("Empty query is accepted")
+	}
+
+	result, err := executor.Execute(Request{Query: "{value}"}, map[string]interface{}{})
+	if err != nil || result.Data != nil || len(result.Errors) != 1 {
+		t.Fatal("Error of the generator isn't written to the result")
+	}
+}
+
+type Newsroom struct {
+	Name     string    `json:"name"`
+	Articles []Article `json:"articles"`
+}
+
+type Article struct {
+	Title string `json:"title"`
+	Body  string `json:"body" fun:"Rbody"`
+}
+
+func (a Article) Rbody(ctx *map[string]interface{}) (string, error) {
+	return "", errors.New("body is hidden")
+}
+
+func TestExecutorErrorLocations(t *testing.T) {
+	executor := NewExecutor(ExecutorConfig[map[string]interface{}]{
+		Parser:     NewQueryParser(QueryParserConfig{}),
+		Generator:  NewResponseGenerator(ResponseGeneratorConfig{PartialResults: true}),
+		DataStruct: Newsroom{Articles: []Article{{Title: "First"}, {Title: "Second"}}},
+	})
+
+	result, err := executor.Execute(Request{Query: "{\n  name # body\n  articles{\n    title, body\n  }\n}"}, map[string]interface{}{})
+	if err != nil {
+		t.Fatal("Execution error: " + err.Error())
+	}
+
+	if len(result.Errors) != 2 {
+		t.Fatal("Not equal")
+	}
+
+	for _, respErr := range result.Errors {
+		if len(respErr.Locations) != 1 || respErr.Locations[0] != (ErrorLocation{Line: 4, Column: 12}) {
+			t.Fatal("Not equal: ", respErr.Locations)
+		}
+	}
+}
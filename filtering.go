@@ -0,0 +1,354 @@
+package hypeql
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Filter and sort of the list field with the `filter:"true"` tag, read from the "where" and "orderBy" arguments:
+//
+//	films(where: "releaseYear gte 2000, name contains Spider", orderBy: "releaseYear desc, name")
+//
+// Conditions are separated by commas and all of them must be true, operators: eq, ne, in (values are separated by "|"), contains, gt, gte, lt, lte.
+// Sort keys are separated by commas, "desc" sorts in descending order.
+// Fields of elements are compared by values of struct fields (resolver functions of elements aren't called)
+type listFilter struct {
+	where   []filterCondition
+	orderBy []sortKey
+}
+
+type filterCondition struct {
+	field    []int // Index of the struct field
+	operator string
+	values   []reflect.Value // Values converted to the field's type ("in" operator has several values)
+}
+
+type sortKey struct {
+	field []int
+	desc  bool
+}
+
+// Operators of conditions
+var filterOperators = []string{"eq", "ne", "in", "contains", "gt", "gte", "lt", "lte"}
+
+// Reads the "where" and "orderBy" arguments of the list field with the "filter" tag.
+// Returns arguments without them (for the resolver function) and nil filter if the field has no tag
+func parseListFilter(sf reflect.StructField, arguments map[string]interface{}, fieldPath []interface{}) (*listFilter, map[string]interface{}, error) {
+	if sf.Tag.Get("filter") != "true" || sf.Type.Kind() != reflect.Slice {
+		return nil, arguments, nil
+	}
+
+	elemType := sf.Type.Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	// Sorting names to report the same invalid argument every time
+	names := []string{}
+	for name := range arguments {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	filter := &listFilter{}
+	resolverArguments := map[string]interface{}{}
+	for _, name := range names {
+		value := arguments[name]
+		if name != "where" && name != "orderBy" {
+			resolverArguments[name] = value
+			continue
+		}
+
+		expression, ok := value.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: argument %s must be string", pathString(fieldPath), name)
+		}
+
+		var err error
+		if name == "where" {
+			filter.where, err = parseConditions(expression, elemType)
+		} else {
+			filter.orderBy, err = parseSortKeys(expression, elemType)
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: argument %s is invalid: %s", pathString(fieldPath), name, err.Error())
+		}
+	}
+
+	return filter, resolverArguments, nil
+}
+
+func parseConditions(expression string, elemType reflect.Type) ([]filterCondition, error) {
+	conditions := []filterCondition{}
+
+	for _, part := range strings.Split(expression, ",") {
+		words := strings.SplitN(strings.TrimSpace(part), " ", 3)
+		if len(words) != 3 {
+			return nil, fmt.Errorf("condition %q must be \"field operator value\"", strings.TrimSpace(part))
+		}
+
+		index, fieldType, err := filterField(elemType, words[0])
+		if err != nil {
+			return nil, err
+		}
+
+		operator := words[1]
+		if !slices.Contains(filterOperators, operator) {
+			return nil, fmt.Errorf("unknown operator %s", operator)
+		}
+
+		if operator == "contains" && fieldType.Kind() != reflect.String {
+			return nil, fmt.Errorf("contains operator can be used only for strings")
+		}
+
+		texts := []string{words[2]}
+		if operator == "in" {
+			texts = strings.Split(words[2], "|")
+		}
+
+		condition := filterCondition{
+			field:    index,
+			operator: operator,
+		}
+
+		for _, text := range texts {
+			value, err := parseFilterValue(text, fieldType)
+			if err != nil {
+				return nil, fmt.Errorf("value %s of %s %s", text, words[0], err.Error())
+			}
+
+			condition.values = append(condition.values, value)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
+
+func parseSortKeys(expression string, elemType reflect.Type) ([]sortKey, error) {
+	keys := []sortKey{}
+
+	for _, part := range strings.Split(expression, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 || len(words) == 2 && words[1] != "asc" && words[1] != "desc" {
+			return nil, fmt.Errorf("sort key %q must be \"field\", \"field asc\" or \"field desc\"", strings.TrimSpace(part))
+		}
+
+		index, _, err := filterField(elemType, words[0])
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, sortKey{
+			field: index,
+			desc:  len(words) == 2 && words[1] == "desc",
+		})
+	}
+
+	return keys, nil
+}
+
+// Finds the struct field by the JSON tag, only fields of basic types can be compared
+func filterField(elemType reflect.Type, name string) ([]int, reflect.Type, error) {
+	if elemType.Kind() == reflect.Struct {
+		for _, sf := range reflect.VisibleFields(elemType) {
+			if sf.Tag.Get("json") != name {
+				continue
+			}
+
+			fieldType := sf.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			if !isArgumentKind(fieldType.Kind()) {
+				return nil, nil, fmt.Errorf("field %s can't be compared", name)
+			}
+
+			return sf.Index, fieldType, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("unknown field %s", name)
+}
+
+// Converts the value of the condition to the type of the field
+func parseFilterValue(text string, t reflect.Type) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("must be bool")
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("must be int")
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("must be uint")
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("must be float")
+		}
+		value.SetFloat(f)
+	}
+
+	return value, nil
+}
+
+// Returns a new slice with elements that match conditions in the sort order (the filter can be nil)
+func (a *listFilter) apply(l reflect.Value) reflect.Value {
+	if a == nil || len(a.where) == 0 && len(a.orderBy) == 0 {
+		return l
+	}
+
+	elements := []reflect.Value{}
+	for i := 0; i < l.Len(); i++ {
+		element := l.Index(i)
+		if len(a.where) != 0 && !a.matches(element) {
+			continue
+		}
+
+		elements = append(elements, element)
+	}
+
+	slices.SortStableFunc(elements, a.compare)
+
+	result := reflect.MakeSlice(l.Type(), len(elements), len(elements))
+	for i, element := range elements {
+		result.Index(i).Set(element)
+	}
+
+	return result
+}
+
+// Returns the value of the struct field of the element (invalid value for nil elements and nil pointer fields)
+func filterFieldValue(element reflect.Value, index []int) reflect.Value {
+	for element.Kind() == reflect.Pointer || element.Kind() == reflect.Interface {
+		if element.IsNil() {
+			return reflect.Value{}
+		}
+
+		element = element.Elem()
+	}
+
+	if element.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	field, err := element.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+
+	return reflect.Indirect(field)
+}
+
+func (a *listFilter) matches(element reflect.Value) bool {
+	for _, condition := range a.where {
+		field := filterFieldValue(element, condition.field)
+		if !field.IsValid() {
+			return false
+		}
+
+		matched := false
+		for _, value := range condition.values {
+			switch condition.operator {
+			case "eq", "in":
+				matched = compareValues(field, value) == 0
+			case "ne":
+				matched = compareValues(field, value) != 0
+			case "contains":
+				matched = strings.Contains(field.String(), value.String())
+			case "gt":
+				matched = compareValues(field, value) > 0
+			case "gte":
+				matched = compareValues(field, value) >= 0
+			case "lt":
+				matched = compareValues(field, value) < 0
+			case "lte":
+				matched = compareValues(field, value) <= 0
+			}
+
+			if matched {
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compares elements by the sort keys, nil elements and nil fields are the last
+func (a *listFilter) compare(x reflect.Value, y reflect.Value) int {
+	for _, key := range a.orderBy {
+		xField, yField := filterFieldValue(x, key.field), filterFieldValue(y, key.field)
+
+		var result int
+		switch {
+		case !xField.IsValid() && !yField.IsValid():
+			continue
+		case !xField.IsValid():
+			return 1
+		case !yField.IsValid():
+			return -1
+		default:
+			result = compareValues(xField, yField)
+		}
+
+		if key.desc {
+			result = -result
+		}
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// Compares values of the same basic kind
+func compareValues(x reflect.Value, y reflect.Value) int {
+	switch x.Kind() {
+	case reflect.String:
+		return cmp.Compare(x.String(), y.String())
+	case reflect.Bool:
+		if x.Bool() == y.Bool() {
+			return 0
+		} else if x.Bool() {
+			return 1
+		}
+		return -1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(x.Int(), y.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(x.Uint(), y.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(x.Float(), y.Float())
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package hypeql
+
+import (
+	"testing"
+)
+
+type Playlist struct {
+	Songs []*Song `json:"songs" fun:"Rsongs" filter:"true"`
+}
+
+type SongsArgs struct {
+	Limit int `arg:"limit" default:"10"`
+}
+
+func (a Playlist) Rsongs(ctx *map[string]any, args SongsArgs) []*Song {
+	rating := func(r float64) *float64 {
+		return &r
+	}
+
+	songs := []*Song{
+		{Title: "Yesterday", Year: 1965, Rating: rating(4.5)},
+		{Title: "Imagine", Year: 1971, Rating: rating(5)},
+		nil,
+		{Title: "Help!", Year: 1965},
+		{Title: "Hey Jude", Year: 1968, Rating: rating(4.5), Explicit: true},
+	}
+
+	return songs[:min(args.Limit, len(songs))]
+}
+
+type Song struct {
+	Title    string   `json:"title"`
+	Year     int      `json:"year"`
+	Rating   *float64 `json:"rating"`
+	Explicit bool     `json:"explicit"`
+}
+
+func TestFiltering(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+	parser := NewQueryParser(QueryParserConfig{})
+
+	queries := map[string]string{
+		`{songs(orderBy: "year desc, title"){title}}`:                       `{"songs":[{"title":"Imagine"},{"title":"Hey Jude"},{"title":"Help!"},{"title":"Yesterday"},null]}`,
+		`{songs(orderBy: "rating desc, title asc"){title}}`:                 `{"songs":[{"title":"Imagine"},{"title":"Hey Jude"},{"title":"Yesterday"},{"title":"Help!"},null]}`,
+		`{songs(where: "year lt 1970, title contains e", limit: 4){title}}`: `{"songs":[{"title":"Yesterday"},{"title":"Help!"}]}`,
+		`{songs(where: "year in 1968|1971, explicit eq false"){title}}`:     `{"songs":[{"title":"Imagine"}]}`,
+		`{songs(where: "rating gte 4.5", orderBy: "title"){title,year}}`:    `{"songs":[{"title":"Hey Jude","year":1968},{"title":"Imagine","year":1971},{"title":"Yesterday","year":1965}]}`,
+		`{songs(where: "title ne Help!"){title}}`:                           `{"songs":[{"title":"Yesterday"},{"title":"Imagine"},{"title":"Hey Jude"}]}`,
+	}
+
+	for query, mustBe := range queries {
+		body, err := parser.Parse(query)
+		if err != nil {
+			t.Fatal("Parse error: " + err.Error())
+		}
+
+		resp, err := generator.Generate(body, Playlist{}, map[string]any{})
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		if resp != mustBe {
+			t.Fatal("Not equal: " + resp)
+		}
+	}
+
+	errorsMustBe := map[string]string{
+		`{songs(where: "author eq Lennon"){title}}`: "songs: argument where is invalid: unknown field author",
+		`{songs(where: "year like 1965"){title}}`:   "songs: argument where is invalid: unknown operator like",
+		`{songs(where: "year eq old"){title}}`:      "songs: argument where is invalid: value old of year must be int",
+		`{songs(orderBy: "year up"){title}}`:        `songs: argument orderBy is invalid: sort key "year up" must be "field", "field asc" or "field desc"`,
+		`{songs(where: 1965){title}}`:               "songs: argument where must be string",
+		`{songs(where: 1965, orderBy: 1){title}}`:   "songs: argument orderBy must be string",
+		`{songs(where: "year eq 50%"){title}}`:      "songs: argument where is invalid: value 50% of year must be int",
+	}
+
+	for query, mustBe := range errorsMustBe {
+		body, err := parser.Parse(query)
+		if err != nil {
+			t.Fatal("Parse error: " + err.Error())
+		}
+
+		_, err = generator.Generate(body, Playlist{}, map[string]any{})
+		if err == nil || err.Error() != mustBe {
+			t.Fatal("Not equal: ", err)
+		}
+	}
+
+	schema, err := Introspect(Playlist{})
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	if arguments := schema.Type("Playlist").Field("songs").Arguments; len(arguments) != 3 || arguments[1].Name != "where" || arguments[2].Name != "orderBy" {
+		t.Fatal("Not equal")
+	}
+}
@@ -0,0 +1,330 @@
+package hypeql
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Executor of objects of the struct type generated by cmd/hypeql-gen, it reads fields and calls methods of the struct directly
+type GeneratedType struct {
+	t      reflect.Type
+	fields []generatedField // Struct fields of generated keys (by indexes the generated code passes)
+
+	object  func(value interface{}) *generatedObject      // Binds the object of the type (nil if the value isn't T or non-nil *T)
+	objects func(value interface{}) ([]interface{}, bool) // Binds objects of the slice ([]T or []*T), nil pointers are returned as nil
+	has     func(value interface{}) bool                  // Checks that the value is T, *T, []T or []*T
+	execute func(x *GeneratedSelection, receiver interface{}) (interface{}, error)
+}
+
+// Struct field of the generated key
+type generatedField struct {
+	sf           reflect.StructField
+	funcName     string       // Name of the resolver function from the "fun" tag
+	resolverType reflect.Type // Type of the resolver function without the receiver (nil if the struct doesn't have it)
+}
+
+// Object of the generated type bound to its executor
+type generatedObject struct {
+	generated    *GeneratedType
+	receiver     interface{}  // Pointer to the struct whose fields are read and methods are called
+	parent       interface{}  // The object passed to middlewares and authorizers (the same value as the receiver of reflection, see "receiverValue")
+	receiverType reflect.Type // Type of the parent (prepared plans are used only for objects of their types)
+	resolve      resolverFunc // Generated call of the "Resolve" method (invalid if the struct doesn't have it)
+}
+
+// Resolver function of the field (or the "Resolve" method) called by reflection or by the generated code
+type resolverFunc struct {
+	t      reflect.Type                                                      // Type of the method without the receiver (nil if there is no function)
+	method reflect.Value                                                     // Method called by reflection (invalid for generated calls)
+	call   func(ctx interface{}, arguments interface{}) (interface{}, error) // Generated call of the method (nil for calls by reflection)
+}
+
+func (a resolverFunc) valid() bool {
+	return a.t != nil
+}
+
+// Returns the resolver of the method called by reflection (invalid if the method is invalid)
+func methodResolver(method reflect.Value) resolverFunc {
+	if !method.IsValid() {
+		return resolverFunc{}
+	}
+
+	return resolverFunc{t: method.Type(), method: method}
+}
+
+// Generated executors by struct types
+var generatedTypes sync.Map
+
+// Registers the executor of the struct type T generated by cmd/hypeql-gen and returns it (generated code passes it as the type of children of list fields).
+// "keys" are JSON tags of generated fields, "execute" processes the selected field by the switch over its tag (see GeneratedSelection).
+// "resolve" calls the "Resolve" method (nil if the struct doesn't have it), it receives names of needed fields.
+// Fields that aren't generated are processed by reflection, types that aren't registered too, so types can be generated one by one.
+// Panics if keys don't match the struct (run the generator again after changing structs)
+func RegisterGenerated[T any](keys []string, execute func(x *GeneratedSelection, receiver *T) (interface{}, error), resolve func(receiver *T, ctx interface{}, fields []string) error) *GeneratedType {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic("hypeql: generated type " + t.String() + " must be struct")
+	}
+
+	// Methods are looked up like the generator does it (see "receiverValue")
+	pointerType := reflect.PointerTo(t)
+	pointer := receiverType(t) == pointerType
+	receiver := reflect.New(t)
+	if !pointer {
+		receiver = receiver.Elem()
+	}
+
+	generated := &GeneratedType{
+		t:      t,
+		fields: make([]generatedField, len(keys)),
+	}
+
+	var resolveType reflect.Type
+	if resolve != nil {
+		method := receiver.MethodByName("Resolve")
+		if !method.IsValid() {
+			panic("hypeql: generated type " + t.String() + " doesn't have the Resolve method")
+		}
+
+		resolveType = method.Type()
+	}
+
+	for i, key := range keys {
+		sf, ok := findStructField(t, key, nil, isValueField)
+		if !ok {
+			panic(fmt.Sprintf("hypeql: generated field %s of %s doesn't match the struct", key, t))
+		}
+
+		generated.fields[i] = generatedField{
+			sf:       sf,
+			funcName: sf.Tag.Get("fun"),
+		}
+
+		if generated.fields[i].funcName != "" {
+			if method := receiver.MethodByName(generated.fields[i].funcName); method.IsValid() {
+				generated.fields[i].resolverType = method.Type()
+			}
+		}
+	}
+
+	bind := func(r *T, parent interface{}, parentType reflect.Type) *generatedObject {
+		object := &generatedObject{
+			generated:    generated,
+			receiver:     r,
+			parent:       parent,
+			receiverType: parentType,
+		}
+
+		if resolve != nil {
+			object.resolve = resolverFunc{t: resolveType, call: func(ctx interface{}, arguments interface{}) (interface{}, error) {
+				return nil, resolve(r, ctx, arguments.([]string))
+			}}
+		}
+
+		return object
+	}
+
+	// Structs are copied like "receiverValue" does it, so "Resolve" methods don't change values of lists
+	bindValue := func(v T) *generatedObject {
+		if pointer {
+			return bind(&v, &v, pointerType)
+		}
+
+		return bind(&v, v, t)
+	}
+
+	generated.object = func(value interface{}) *generatedObject {
+		switch v := value.(type) {
+		case *T:
+			if v != nil {
+				return bind(v, v, pointerType)
+			}
+		case T:
+			return bindValue(v)
+		}
+
+		return nil
+	}
+
+	generated.objects = func(value interface{}) ([]interface{}, bool) {
+		switch l := value.(type) {
+		case []T:
+			objects := make([]interface{}, len(l))
+			for i := range l {
+				objects[i] = bindValue(l[i])
+			}
+
+			return objects, true
+		case []*T:
+			objects := make([]interface{}, len(l))
+			for i, p := range l {
+				if p != nil {
+					objects[i] = bind(p, p, pointerType)
+				}
+			}
+
+			return objects, true
+		}
+
+		return nil, false
+	}
+
+	generated.has = func(value interface{}) bool {
+		switch value.(type) {
+		case T, *T, []T, []*T:
+			return true
+		}
+
+		return false
+	}
+
+	generated.execute = func(x *GeneratedSelection, receiver interface{}) (interface{}, error) {
+		return execute(x, receiver.(*T))
+	}
+
+	generatedTypes.Store(t, generated)
+
+	return generated
+}
+
+// Returns the object bound to the generated type of children (nil if the type is nil or the value doesn't have it)
+func (a *GeneratedType) bind(value interface{}) *generatedObject {
+	if a == nil {
+		return nil
+	}
+
+	return a.object(value)
+}
+
+// Returns objects of the slice bound to the generated type of children (false if the type is nil or the slice doesn't have it)
+func (a *GeneratedType) bindAll(value interface{}) ([]interface{}, bool) {
+	if a == nil {
+		return nil, false
+	}
+
+	return a.objects(value)
+}
+
+// Checks that the value is an object or a slice of objects of the generated type of children (false if the type is nil)
+func (a *GeneratedType) owns(value interface{}) bool {
+	return a != nil && a.has(value)
+}
+
+// Returns the generated object of the value (nil if its type isn't generated or the "IgnoreGenerated" option is set).
+// Objects bound by the generated type of children are returned as they are
+func (a responseGenerator) generatedObject(ds interface{}) *generatedObject {
+	if object, ok := ds.(*generatedObject); ok {
+		return object
+	}
+
+	if a.Config.IgnoreGenerated || ds == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(ds)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	generated, ok := generatedTypes.Load(t)
+	if !ok {
+		return nil
+	}
+
+	return generated.(*GeneratedType).object(ds)
+}
+
+// Field of the generated object selected by the request.
+// The generated code switches over the tag of the field and passes the value of the struct field and the call of its resolver function to it
+type GeneratedSelection struct {
+	generator *responseGenerator
+	object    *generatedObject
+	sel       selection
+	ctx       interface{}
+	path      []interface{} // Path of the object
+	deep      uint64
+	exec      *execution
+	stream    *streamWriter // List fields are written to it when the response is streamed (nil if it isn't)
+}
+
+// Returns the JSON tag of the selected field
+func (a *GeneratedSelection) Key() string {
+	return a.sel.key
+}
+
+// Processes the selected field like reflection does it: "index" is the index of the field's tag in keys of the type,
+// "resolver" calls the resolver function (nil if the struct doesn't have it).
+// Objects of the list field are processed by the executor of "children" (nil if their type isn't generated)
+func (a *GeneratedSelection) Field(index int, value interface{}, resolver func(ctx interface{}, arguments interface{}) (interface{}, error), children *GeneratedType) (interface{}, error) {
+	generated := a.object.generated.fields[index]
+
+	// Reflection doesn't find the field if it can't be a list field
+	if a.sel.list && !isListField(generated.sf, a.generator.Config.Scalars) {
+		return a.Reflect()
+	}
+
+	field := objectField{
+		sf:       generated.sf,
+		value:    value,
+		funcName: generated.funcName,
+		parent:   a.object.parent,
+		children: children,
+	}
+
+	if resolver != nil && generated.resolverType != nil {
+		field.resolver = resolverFunc{t: generated.resolverType, call: resolver}
+	}
+
+	fieldPath := appendPath(a.path, a.sel.key)
+	if !a.sel.list {
+		return a.generator.resolveValue(a.sel, field, a.ctx, fieldPath, a.exec)
+	}
+
+	list, pending, err := a.generator.resolveList(a.sel, field, a.ctx, fieldPath, a.deep, a.exec)
+	if a.stream != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, a.generator.streamList(a.stream, a.sel, a.ctx, fieldPath, list, pending, children, a.deep, a.exec)
+	}
+
+	if pending != nil {
+		return pending, nil
+	}
+
+	if err != nil || list == nil {
+		return nil, err
+	}
+
+	return a.generator.generateObjects(a.sel, a.ctx, fieldPath, list, children, a.deep, a.exec)
+}
+
+// Processes the selected field by reflection (the generated code calls it for fields that aren't generated)
+func (a *GeneratedSelection) Reflect() (interface{}, error) {
+	branchRefVal := reflect.ValueOf(a.object.parent)
+
+	if a.stream != nil && a.sel.list {
+		return nil, a.generator.streamFieldList(a.stream, a.sel, a.ctx, a.path, branchRefVal, a.deep, a.exec)
+	}
+
+	return a.generator.reflectedField(branchRefVal, a.path, a.deep)(a.sel, a.ctx, a.exec)
+}
+
+// Calls the "Resolve" method and receives values of selected fields of the object by its generated executor
+func (a responseGenerator) generateGenerated(object *generatedObject, selections []selection, keys []string, ctx interface{}, path []interface{}, deep uint64, exec *execution) (interface{}, error) {
+	generator := &a
+
+	return a.generateFields(object.resolve, object.parent, selections, keys, ctx, path, exec, func(sel selection, ctx interface{}, exec *execution) (interface{}, error) {
+		return object.generated.execute(&GeneratedSelection{
+			generator: generator,
+			object:    object,
+			sel:       sel,
+			ctx:       ctx,
+			path:      path,
+			deep:      deep,
+			exec:      exec,
+		}, object.receiver)
+	})
+}
@@ -0,0 +1,220 @@
+package hypeql
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type Gallery struct {
+	Name   string  `json:"name"`
+	Photos []Photo `json:"photos" fun:"Rphotos" filter:"true"`
+	Owner  string  `json:"owner" fun:"Rowner"`
+	Cover  *Photo  `json:"cover"`
+}
+
+type GalleryPhotosArgs struct {
+	Limit int `arg:"limit" default:"10"`
+}
+
+func (a Gallery) Rphotos(ctx *map[string]interface{}, args GalleryPhotosArgs) ([]Photo, error) {
+	if args.Limit < 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	photos := []Photo{{Id: 3, Title: "Sea"}, {Id: 1, Title: "Forest"}, {Id: 2, Title: "Mountains"}}
+	return photos[:min(args.Limit, len(photos))], nil
+}
+
+func (a Gallery) Rowner(ctx *map[string]interface{}) string {
+	return (*ctx)["user"].(string)
+}
+
+type Photo struct {
+	Id     int    `json:"id"`
+	Title  string `json:"title"`
+	Viewed bool   `json:"viewed"`
+}
+
+func (a *Photo) Resolve(ctx *map[string]interface{}, fields []string) error {
+	if a.Id == 0 {
+		return errors.New("photo isn't found")
+	}
+
+	a.Viewed = true
+	return nil
+}
+
+// Count of calls of generated executors
+var generatedCalls atomic.Int64
+
+// Executors in the style of cmd/hypeql-gen
+var (
+	generatedGallery *GeneratedType
+	generatedPhoto   *GeneratedType
+)
+
+func init() {
+	generatedGallery = RegisterGenerated[Gallery]([]string{"name", "photos", "cover"}, executeGallery, nil)
+	generatedPhoto = RegisterGenerated[Photo]([]string{"id", "title", "viewed"}, executePhoto, resolvePhoto)
+}
+
+func executeGallery(x *GeneratedSelection, receiver *Gallery) (interface{}, error) {
+	generatedCalls.Add(1)
+
+	switch x.Key() {
+	case "name":
+		return x.Field(0, receiver.Name, nil, nil)
+	case "photos":
+		return x.Field(1, receiver.Photos, func(ctx interface{}, arguments interface{}) (interface{}, error) {
+			value, err := receiver.Rphotos(ctx.(*map[string]interface{}), arguments.(GalleryPhotosArgs))
+			if err != nil {
+				return nil, err
+			}
+			return value, nil
+		}, generatedPhoto)
+	case "cover":
+		return x.Field(2, receiver.Cover, nil, generatedPhoto)
+	}
+
+	// "owner" is processed by reflection
+	return x.Reflect()
+}
+
+func executePhoto(x *GeneratedSelection, receiver *Photo) (interface{}, error) {
+	generatedCalls.Add(1)
+
+	switch x.Key() {
+	case "id":
+		return x.Field(0, receiver.Id, nil, nil)
+	case "title":
+		return x.Field(1, receiver.Title, nil, nil)
+	case "viewed":
+		return x.Field(2, receiver.Viewed, nil, nil)
+	}
+
+	return x.Reflect()
+}
+
+func resolvePhoto(receiver *Photo, ctx interface{}, fields []string) error {
+	generatedCalls.Add(1)
+	return receiver.Resolve(ctx.(*map[string]interface{}), fields)
+}
+
+func TestGenerated(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+
+	queries := []string{
+		`{name,owner,photos(limit: 2, orderBy: "id"){id,title,viewed}}`,
+		`{photos(where: "id gte 2"){title}}`,
+		`{photos(limit: -1){id}}`,
+		`{photos{id,unknown}}`,
+		`{cover{id,title,viewed},photos(limit: 1){viewed}}`,
+		`{photos,name{id}}`,
+	}
+
+	galleries := []*Gallery{
+		{Name: "Nature"},
+		{Name: "Lakes", Cover: &Photo{Id: 7, Title: "Lake"}},
+		{Name: "Empty", Cover: &Photo{Title: "Nothing"}},
+	}
+
+	for _, query := range queries {
+		parsed, err := parser.Parse(query)
+		if err != nil {
+			t.Fatal("Parsing error: " + err.Error())
+		}
+
+		for _, gallery := range galleries {
+			for _, partial := range []bool{false, true} {
+				results := []string{}
+				for _, ignore := range []bool{true, false} {
+					generator := NewResponseGenerator(ResponseGeneratorConfig{IgnoreGenerated: ignore, PartialResults: partial, Parallel: partial})
+					before := generatedCalls.Load()
+
+					result, err := generator.Generate(parsed, *gallery, map[string]interface{}{"user": "John"})
+					if err != nil {
+						result = "error: " + err.Error()
+					}
+
+					var buffer bytes.Buffer
+					if err := generator.GenerateTo(&buffer, parsed, *gallery, map[string]interface{}{"user": "John"}); err == nil && buffer.String() != result {
+						t.Fatal("Not equal: " + buffer.String() + " " + result)
+					}
+
+					if ignore != (generatedCalls.Load() == before) {
+						t.Fatal("Not equal")
+					}
+
+					results = append(results, result)
+				}
+
+				if results[0] != results[1] {
+					t.Fatal("Not equal: " + results[0] + " " + results[1])
+				}
+			}
+		}
+	}
+
+	parsed, _ := parser.Parse(`{name,owner,cover{id,viewed},photos(limit: 2, orderBy: "id"){id,title,viewed}}`)
+	result, err := NewResponseGenerator(ResponseGeneratorConfig{}).Generate(parsed, galleries[1], map[string]interface{}{"user": "John"})
+	if err != nil {
+		t.Fatal("Generating error: " + err.Error())
+	}
+
+	if result != `{"name":"Lakes","owner":"John","cover":{"id":7,"viewed":true},"photos":[{"id":1,"title":"Forest","viewed":true},{"id":3,"title":"Sea","viewed":true}]}` {
+		t.Fatal("Not equal: " + result)
+	}
+}
+
+func TestGeneratedPrepared(t *testing.T) {
+	parsed, _ := NewQueryParser(QueryParserConfig{}).Parse(`{name,photos(limit: $limit){id,viewed}}`)
+	prepared, err := NewResponseGenerator(ResponseGeneratorConfig{}).Prepare(parsed, Gallery{Name: "Nature"})
+	if err != nil {
+		t.Fatal("Preparing error: " + err.Error())
+	}
+
+	before := generatedCalls.Load()
+	result, err := prepared.Execute(map[string]interface{}{}, map[string]interface{}{"limit": 1})
+	if err != nil {
+		t.Fatal("Executing error: " + err.Error())
+	}
+
+	if generatedCalls.Load() == before {
+		t.Fatal("Not equal")
+	}
+
+	photos, _ := result.Data.Get("photos")
+	if len(photos.([]interface{})) != 1 {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestRegisterGeneratedMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Not panicked")
+		}
+	}()
+
+	RegisterGenerated[Photo]([]string{"author"}, executePhoto, nil)
+}
+
+func BenchmarkGenerated(b *testing.B) {
+	parsed, _ := NewQueryParser(QueryParserConfig{}).Parse(`{name,photos(limit: 3){id,title,viewed}}`)
+
+	for _, ignore := range []bool{true, false} {
+		generator := NewResponseGenerator(ResponseGeneratorConfig{IgnoreGenerated: ignore})
+		name := "Generated"
+		if ignore {
+			name = "Reflection"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				generator.Generate(parsed, Gallery{}, map[string]interface{}{"user": "John"})
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package hypeqlclient sends HypeQL queries built from Go structs to HTTP servers (served by hypeqlhttp) and decodes responses into these structs
+package hypeqlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+// Sends queries to the server, it's safe for concurrent use
+type client struct {
+	Config Config
+}
+
+type Config struct {
+	URL        string       // Address of the HypeQL endpoint
+	HTTPClient *http.Client // Client that sends requests, stay nil to use http.DefaultClient
+	Header     http.Header  // Headers added to every request (authorization, for example)
+}
+
+func NewClient(config Config) client {
+	return client{
+		Config: config,
+	}
+}
+
+// Errors of the response ("errors" array), the data is decoded too if the server returned it (partial results)
+type ResponseErrors []*hypeql.ResponseError
+
+func (a ResponseErrors) Error() string {
+	messages := make([]string, len(a))
+	for i, err := range a {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Error of the request with the HTTP status code that isn't 200, "Err" is ResponseErrors if the server described the error
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (a *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", a.Code, a.Err.Error())
+}
+
+func (a *StatusError) Unwrap() error {
+	return a.Err
+}
+
+// Builds the query from fields of the result struct (see Build), sends it with variables and decodes the data into the result.
+// Errors of fields are returned as ResponseErrors, fields that are decoded before them stay in the result
+func (a client) Query(ctx context.Context, result interface{}, variables map[string]interface{}) error {
+	query, err := Build(result)
+	if err != nil {
+		return err
+	}
+
+	return a.Do(ctx, query, variables, result)
+}
+
+// Sends the query text with variables and decodes the data into the result (pointer to the value that encoding/json decodes, nil to ignore the data)
+func (a client) Do(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(hypeql.Request{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for key, values := range a.Config.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	httpClient := a.Config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return decodeResponse(response, result)
+}
+
+// Response of the server: {"data": ..., "errors": [...]}
+type envelope struct {
+	Data   json.RawMessage `json:"data"`
+	Errors ResponseErrors  `json:"errors"`
+}
+
+func decodeResponse(response *http.Response, result interface{}) error {
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	decoded := envelope{}
+	decodeErr := json.Unmarshal(content, &decoded)
+
+	if response.StatusCode != http.StatusOK {
+		if decodeErr == nil && len(decoded.Errors) != 0 {
+			return &StatusError{response.StatusCode, decoded.Errors}
+		}
+
+		return &StatusError{response.StatusCode, errors.New(http.StatusText(response.StatusCode))}
+	}
+
+	if decodeErr != nil {
+		return errors.New("response must be JSON object with the data: " + decodeErr.Error())
+	}
+
+	if result != nil && len(decoded.Data) != 0 && string(decoded.Data) != "null" {
+		if err := json.Unmarshal(decoded.Data, result); err != nil {
+			return err
+		}
+	}
+
+	if len(decoded.Errors) != 0 {
+		return decoded.Errors
+	}
+
+	return nil
+}
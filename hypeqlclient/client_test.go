@@ -0,0 +1,144 @@
+package hypeqlclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dadencukillia/hypeql"
+	"github.com/dadencukillia/hypeql/hypeqlhttp"
+)
+
+type Session struct {
+	Token string
+}
+
+type Response struct {
+	Greeting string `json:"greeting" fun:"Rgreeting"`
+	Notes    []Note `json:"notes" fun:"Rnotes"`
+	Failing  string `json:"failing" fun:"Rfailing"`
+}
+
+type NotesArgs struct {
+	Count int `arg:"count" default:"1"`
+}
+
+func (a Response) Rgreeting(ctx *Session) string {
+	return "Hello, " + ctx.Token
+}
+
+func (a Response) Rnotes(ctx *Session, args NotesArgs) []Note {
+	notes := []Note{}
+	for i := 0; i < args.Count; i++ {
+		notes = append(notes, Note{Text: "note", Id: i})
+	}
+
+	return notes
+}
+
+func (a Response) Rfailing(ctx *Session) (string, error) {
+	return "", errors.New("failed")
+}
+
+type Note struct {
+	Id   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// Result structs of the client
+type NotesQuery struct {
+	Greeting string `json:"greeting"`
+	Notes    []struct {
+		Id int `json:"id"`
+	} `json:"notes" args:"count: $count"`
+}
+
+type FailingQuery struct {
+	Greeting string `json:"greeting"`
+	Failing  string `json:"failing"`
+}
+
+type UnknownQuery struct {
+	Unknown string `json:"unknown"`
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	generator, err := hypeql.NewTypedResponseGenerator[Session](hypeql.ResponseGeneratorConfig{
+		PartialResults: true,
+	}, Response{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	return httptest.NewServer(hypeqlhttp.NewHandler(hypeqlhttp.Config[*Session]{
+		Parser:     hypeql.NewQueryParser(hypeql.QueryParserConfig{}),
+		Generator:  generator,
+		DataStruct: Response{},
+		Context: func(w http.ResponseWriter, r *http.Request) (*Session, error) {
+			return &Session{Token: r.Header.Get("Authorization")}, nil
+		},
+	}))
+}
+
+func TestQuery(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := NewClient(Config{
+		URL:    server.URL,
+		Header: http.Header{"Authorization": {"John"}},
+	})
+
+	result := NotesQuery{}
+	if err := client.Query(context.Background(), &result, map[string]interface{}{"count": 3}); err != nil {
+		t.Fatal("Query error: " + err.Error())
+	}
+
+	if result.Greeting != "Hello, John" || len(result.Notes) != 3 || result.Notes[2].Id != 2 {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := NewClient(Config{URL: server.URL})
+
+	// Partial results: data and errors of fields
+	result := FailingQuery{}
+	err := client.Query(context.Background(), &result, nil)
+
+	var respErrs ResponseErrors
+	if !errors.As(err, &respErrs) || len(respErrs) != 1 || respErrs[0].Message != "failed" || respErrs[0].Path[0] != "failing" {
+		t.Fatal("Not equal")
+	}
+
+	if result.Greeting != "Hello, " {
+		t.Fatal("Not equal")
+	}
+
+	// Error of the whole query
+	if err := client.Query(context.Background(), &UnknownQuery{}, nil); err == nil || !errors.As(err, &respErrs) {
+		t.Fatal("Not error")
+	}
+
+	// Request error with the status code
+	err = client.Query(context.Background(), &NotesQuery{}, nil)
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusBadRequest || !errors.As(err, &respErrs) {
+		t.Fatal("Not equal")
+	}
+
+	// Server that isn't HypeQL
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	err = NewClient(Config{URL: notFound.URL}).Do(context.Background(), "{greeting}", nil, nil)
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+		t.Fatal("Not equal")
+	}
+}
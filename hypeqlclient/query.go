@@ -0,0 +1,114 @@
+package hypeqlclient
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Built queries by result types
+var queries sync.Map
+
+// Returns the query text that selects fields of the result struct (pointer to struct or struct type value).
+// Fields are selected by their JSON tags, fields of structs and slices of structs are selected with their fields.
+// Arguments of the field are written to the "args" tag:
+//
+//	Films []Film `json:"films" args:"p: $page, orderBy: \"name\""`
+//
+// Fields without JSON tags (or with "-") aren't selected
+func Build(result interface{}) (string, error) {
+	t := reflect.TypeOf(result)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", errors.New("result must be struct or pointer to struct")
+	}
+
+	if query, ok := queries.Load(t); ok {
+		return query.(string), nil
+	}
+
+	var builder strings.Builder
+	if err := buildSelection(&builder, t, []reflect.Type{}); err != nil {
+		return "", err
+	}
+
+	query := builder.String()
+	queries.Store(t, query)
+
+	return query, nil
+}
+
+// Writes fields of the struct type in curly brackets, "types" are struct types of the path (to find recursive types)
+func buildSelection(builder *strings.Builder, t reflect.Type, types []reflect.Type) error {
+	for _, parent := range types {
+		if parent == t {
+			return errors.New("type " + t.String() + " is recursive, its query can't be built")
+		}
+	}
+	types = append(types, t)
+
+	builder.WriteString("{")
+
+	count := 0
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !sf.IsExported() {
+			continue
+		}
+
+		if count != 0 {
+			builder.WriteString(",")
+		}
+		count++
+
+		builder.WriteString(name)
+		if args := sf.Tag.Get("args"); args != "" {
+			builder.WriteString("(" + args + ")")
+		}
+
+		if object, ok := objectType(sf.Type); ok {
+			if err := buildSelection(builder, object, types); err != nil {
+				return err
+			}
+		}
+	}
+
+	if count == 0 {
+		return errors.New("struct " + t.String() + " doesn't have fields with JSON tags")
+	}
+
+	builder.WriteString("}")
+
+	return nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Returns the struct type of the object field (struct, slice of structs or pointers to them).
+// Structs that decode themselves (time.Time, for example) are values
+func objectType(t reflect.Type) (reflect.Type, bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	pointer := reflect.PointerTo(t)
+	if pointer.Implements(jsonUnmarshalerType) || pointer.Implements(textUnmarshalerType) {
+		return nil, false
+	}
+
+	return t, true
+}
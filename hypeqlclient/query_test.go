@@ -0,0 +1,57 @@
+package hypeqlclient
+
+import (
+	"testing"
+	"time"
+)
+
+type Library struct {
+	Name    string    `json:"name,omitempty"`
+	Updated time.Time `json:"updated"`
+	Tags    []string  `json:"tags"`
+	Books   []Book    `json:"books" args:"limit: $limit, orderBy: \"title\""`
+	Owner   *Owner    `json:"owner"`
+	Hidden  string    `json:"-"`
+	Local   string
+}
+
+type Book struct {
+	Title string `json:"title"`
+}
+
+type Owner struct {
+	Name string `json:"name"`
+}
+
+type Chapter struct {
+	Title    string    `json:"title"`
+	Sections []Chapter `json:"sections"`
+}
+
+func TestBuild(t *testing.T) {
+	query, err := Build(&Library{})
+	if err != nil {
+		t.Fatal("Building error: " + err.Error())
+	}
+
+	if query != `{name,updated,tags,books(limit: $limit, orderBy: "title"){title},owner{name}}` {
+		t.Fatal("Not equal: " + query)
+	}
+
+	// Cached query
+	if cached, _ := Build(Library{}); cached != query {
+		t.Fatal("Not equal")
+	}
+
+	if _, err := Build(Chapter{}); err == nil {
+		t.Fatal("Not error")
+	}
+
+	if _, err := Build([]Book{}); err == nil {
+		t.Fatal("Not error")
+	}
+
+	if _, err := Build(struct{ Name string }{}); err == nil {
+		t.Fatal("Not error")
+	}
+}
@@ -0,0 +1,58 @@
+package hypeqlhttp
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+// Page of the query explorer (HTML, styles and scripts are in the one file, so it doesn't load anything from other servers)
+//
+//go:embed explorer.html
+var explorerPage []byte
+
+// Generators that describe response structs with their custom scalar types
+type introspector interface {
+	Introspect(dataStruct interface{}) (*hypeql.Schema, error)
+}
+
+// Serves the explorer page to browsers and the schema to the explorer page.
+// Returns false if the request is a query
+func (a httpHandler[C]) serveExplorer(w http.ResponseWriter, r *http.Request) bool {
+	if !a.Config.Explorer || r.Method != http.MethodGet || r.URL.Query().Has("query") || r.URL.Query().Has("extensions") {
+		return false
+	}
+
+	if r.URL.Query().Has("schema") {
+		schema, err := a.schema()
+		if err != nil {
+			writeError(w, err)
+			return true
+		}
+
+		writeJSON(w, http.StatusOK, schema)
+		return true
+	}
+
+	// Browsers open the page, other clients receive the "query is required" error
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(explorerPage)
+
+	return true
+}
+
+// Describes the response struct
+func (a httpHandler[C]) schema() (*hypeql.Schema, error) {
+	if generator, ok := a.Config.Generator.(introspector); ok {
+		return generator.Introspect(a.Config.DataStruct)
+	}
+
+	return hypeql.Introspect(a.Config.DataStruct)
+}
@@ -0,0 +1,74 @@
+package hypeqlhttp
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+func TestExplorer(t *testing.T) {
+	generator, err := hypeql.NewTypedResponseGenerator[Session](hypeql.ResponseGeneratorConfig{}, Response{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	handler := NewHandler(Config[*Session]{
+		Parser:     hypeql.NewQueryParser(hypeql.QueryParserConfig{}),
+		Generator:  generator,
+		DataStruct: Response{},
+		Explorer:   true,
+	})
+
+	// Browsers receive the page
+	req := httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	page := recorder.Body.String()
+	if recorder.Code != 200 || recorder.Header().Get("Content-Type") != "text/html; charset=utf-8" || !strings.Contains(page, "<title>HypeQL Explorer</title>") {
+		t.Fatal("Not equal")
+	}
+
+	// The page doesn't load anything from other servers
+	if strings.Contains(page, `src="`) || strings.Contains(page, `href="`) || strings.Contains(page, "https://") {
+		t.Fatal("Page has external resources")
+	}
+
+	// Schema for autocomplete
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api?schema=1", nil))
+
+	schema := &hypeql.Schema{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), schema); err != nil {
+		t.Fatal("Decode error: " + err.Error())
+	}
+
+	if recorder.Code != 200 || schema.Root != "Response" || schema.Type("Response").Field("notes").Arguments[0].Name != "count" {
+		t.Fatal("Not equal: " + recorder.Body.String())
+	}
+
+	// Queries are still processed
+	req = httptest.NewRequest("GET", "/api?query={greeting}", nil)
+	req.Header.Set("Accept", "text/html")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Body.String() != `{"data":{"greeting":"Hello, "}}` {
+		t.Fatal("Not equal: " + recorder.Body.String())
+	}
+
+	// Explorer is disabled by default
+	handler.Config.Explorer = false
+	req = httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Accept", "text/html")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != 400 {
+		t.Fatal("Not equal")
+	}
+}
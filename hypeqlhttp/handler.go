@@ -0,0 +1,249 @@
+// Package hypeqlhttp serves HypeQL queries over HTTP
+package hypeqlhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+// Parses queries (see hypeql.Parser)
+type Parser = hypeql.Parser
+
+// Processes parsed queries (see hypeql.Generator), it has the same methods as hypeql.Generator because generic aliases require Go 1.24
+type Generator[C any] interface {
+	hypeql.Generator[C]
+}
+
+// Struct that serves queries, it's the http.Handler
+type httpHandler[C any] struct {
+	Config Config[C]
+}
+
+type Config[C any] struct {
+	Parser      Parser
+	Generator   Generator[C]
+	DataStruct  interface{}                                             // Instance of the response struct (can be filled if there are not Resolver functions)
+	Context     func(w http.ResponseWriter, r *http.Request) (C, error) // Creates the initial context of the request (and can set headers or cookies), stay nil to use the empty context
+	MaxBodySize int64                                                   // Limits size of request bodies in bytes, stay 0 if unlimited
+	Explorer    bool                                                    // Serve the query explorer page to browsers (GET requests without the query) and the schema for it (GET with the "schema" parameter)
+
+	MaxBatchSize      uint64 // Limits count of queries in batched requests (JSON arrays of envelopes), stay 0 if unlimited
+	ConcurrentBatches bool   // Execute queries of batched requests concurrently (every query receives its own copy of the context)
+
+	PersistedQueries       hypeql.PersistedQueryStore // Queries that clients can send by their hashes, stay nil if not used
+	RegisterQueries        bool                       // Save unknown queries sent with their hashes to the store (automatic persisted queries, use it in development)
+	StrictPersistedQueries bool                       // Execute only queries of the store (allowlist)
+}
+
+func NewHandler[C any](config Config[C]) httpHandler[C] {
+	return httpHandler[C]{
+		Config: config,
+	}
+}
+
+// Query of the request: {"query": "...", "variables": {...}, "operationName": "..."}
+type Envelope = hypeql.Request
+
+// Error of the request with the HTTP status code (the context callback can return it to reject the request)
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (a *StatusError) Error() string {
+	return a.Err.Error()
+}
+
+func (a *StatusError) Unwrap() error {
+	return a.Err
+}
+
+// Serves queries:
+//   - GET with the "query", "variables" (JSON object), "operationName" and "extensions" (JSON object with the hash of the persisted query) URL parameters;
+//   - POST with the JSON envelope (application/json) or the JSON array of envelopes (batch);
+//   - POST with the query as the body (application/graphql, text/plain or without the content type).
+//
+// Responses are JSON objects {"data": ..., "errors": [...]} (arrays of them in the order of envelopes for batches),
+// errors of fields (and the whole query) are returned with 200 status
+func (a httpHandler[C]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.serveExplorer(w, r) {
+		return
+	}
+
+	envelope, batch, err := a.readEnvelope(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, err := a.newContext(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	executor := hypeql.NewExecutor(hypeql.ExecutorConfig[C]{
+		Parser:       a.Config.Parser,
+		Generator:    a.Config.Generator,
+		DataStruct:   a.Config.DataStruct,
+		MaxBatchSize: a.Config.MaxBatchSize,
+		Concurrent:   a.Config.ConcurrentBatches,
+
+		PersistedQueries:       a.Config.PersistedQueries,
+		RegisterQueries:        a.Config.RegisterQueries,
+		StrictPersistedQueries: a.Config.StrictPersistedQueries,
+	})
+
+	if batch != nil {
+		results, err := executor.ExecuteBatch(batch, ctx)
+		if err != nil {
+			writeError(w, &StatusError{http.StatusBadRequest, err})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	result, err := executor.Execute(envelope, ctx)
+	if err != nil {
+		writeError(w, &StatusError{http.StatusBadRequest, err})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Reads the query of the request, the batch is returned instead of the envelope if the body is the JSON array
+func (a httpHandler[C]) readEnvelope(w http.ResponseWriter, r *http.Request) (Envelope, []Envelope, error) {
+	envelope := Envelope{}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		envelope.Query = query.Get("query")
+		envelope.OperationName = query.Get("operationName")
+
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &envelope.Variables); err != nil {
+				return envelope, nil, &StatusError{http.StatusBadRequest, errors.New("variables must be JSON object")}
+			}
+		}
+
+		if extensions := query.Get("extensions"); extensions != "" {
+			if err := json.Unmarshal([]byte(extensions), &envelope.Extensions); err != nil {
+				return envelope, nil, &StatusError{http.StatusBadRequest, errors.New("extensions must be JSON object")}
+			}
+		}
+
+	case http.MethodPost:
+		body, err := a.readBody(w, r)
+		if err != nil {
+			return envelope, nil, err
+		}
+
+		mediaType := ""
+		if contentType := r.Header.Get("Content-Type"); contentType != "" {
+			if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+				return envelope, nil, &StatusError{http.StatusUnsupportedMediaType, errors.New("invalid content type")}
+			}
+		}
+
+		switch mediaType {
+		case "application/json":
+			if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+				batch := []Envelope{}
+				if err := json.Unmarshal(body, &batch); err != nil {
+					return envelope, nil, &StatusError{http.StatusBadRequest, errors.New("body must be JSON array of objects with queries")}
+				}
+
+				return envelope, batch, nil
+			}
+
+			if err := json.Unmarshal(body, &envelope); err != nil {
+				return envelope, nil, &StatusError{http.StatusBadRequest, errors.New("body must be JSON object with the query")}
+			}
+		case "application/graphql", "text/plain", "":
+			envelope.Query = string(body)
+		default:
+			return envelope, nil, &StatusError{http.StatusUnsupportedMediaType, errors.New("unsupported content type " + mediaType)}
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		return envelope, nil, &StatusError{http.StatusMethodNotAllowed, errors.New("method " + r.Method + " is not allowed")}
+	}
+
+	return envelope, nil, nil
+}
+
+// Reads the body of the request with the size limit
+func (a httpHandler[C]) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	reader := r.Body
+	if a.Config.MaxBodySize != 0 {
+		reader = http.MaxBytesReader(w, r.Body, a.Config.MaxBodySize)
+	}
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, &StatusError{http.StatusRequestEntityTooLarge, errors.New("body is too large")}
+		}
+
+		return nil, &StatusError{http.StatusBadRequest, errors.New("body can't be read")}
+	}
+
+	return body, nil
+}
+
+// Creates the initial context by the callback or the empty context (empty map or pointer to zero struct)
+func (a httpHandler[C]) newContext(w http.ResponseWriter, r *http.Request) (C, error) {
+	if a.Config.Context != nil {
+		return a.Config.Context(w, r)
+	}
+
+	var ctx C
+	switch t := reflect.TypeOf(&ctx).Elem(); t.Kind() {
+	case reflect.Map:
+		ctx = reflect.MakeMap(t).Interface().(C)
+	case reflect.Pointer:
+		ctx = reflect.New(t.Elem()).Interface().(C)
+	}
+
+	return ctx, nil
+}
+
+// Writes the error of the request with its status code (500 if the error isn't *StatusError)
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		code = statusErr.Code
+	}
+
+	writeJSON(w, code, &hypeql.Result{
+		Errors: []*hypeql.ResponseError{hypeql.NewResponseError(err, nil)},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	out, err := json.Marshal(value)
+	if err != nil {
+		code = http.StatusInternalServerError
+		out = []byte(`{"errors":[{"message":"response can't be encoded"}]}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(out)
+}
@@ -0,0 +1,262 @@
+package hypeqlhttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dadencukillia/hypeql"
+)
+
+type Session struct {
+	User string
+}
+
+type Response struct {
+	Greeting string `json:"greeting" fun:"Rgreeting"`
+	Notes    []Note `json:"notes" fun:"Rnotes"`
+	Failing  string `json:"failing" fun:"Rfailing"`
+}
+
+type NotesArgs struct {
+	Count int `arg:"count" default:"1"`
+}
+
+func (a Response) Rgreeting(ctx *Session) string {
+	return "Hello, " + ctx.User
+}
+
+func (a Response) Rnotes(ctx *Session, args NotesArgs) []Note {
+	notes := []Note{}
+	for i := 0; i < args.Count; i++ {
+		notes = append(notes, Note{Text: "note"})
+	}
+
+	return notes
+}
+
+func (a Response) Rfailing(ctx *Session) (string, error) {
+	return "", errors.New("failed")
+}
+
+type Note struct {
+	Text string `json:"text"`
+}
+
+func newTestServer(t *testing.T, partialResults bool) *httptest.Server {
+	generator, err := hypeql.NewTypedResponseGenerator[Session](hypeql.ResponseGeneratorConfig{
+		PartialResults: partialResults,
+	}, Response{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	return httptest.NewServer(NewHandler(Config[*Session]{
+		Parser:      hypeql.NewQueryParser(hypeql.QueryParserConfig{}),
+		Generator:   generator,
+		DataStruct:  Response{},
+		MaxBodySize: 200,
+		Context: func(w http.ResponseWriter, r *http.Request) (*Session, error) {
+			user := r.Header.Get("X-User")
+			if user == "" {
+				return nil, &StatusError{http.StatusUnauthorized, errors.New("unauthorized")}
+			}
+
+			return &Session{User: user}, nil
+		},
+	}))
+}
+
+func request(t *testing.T, method string, target string, contentType string, body string) (int, string, string) {
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal("Request error: " + err.Error())
+	}
+
+	req.Header.Set("X-User", "John")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal("Request error: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	out, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(out)
+}
+
+func TestHandler(t *testing.T) {
+	server := newTestServer(t, true)
+	defer server.Close()
+
+	type testCase struct {
+		method      string
+		target      string
+		contentType string
+		body        string
+		code        int
+		resp        string
+	}
+
+	cases := []testCase{
+		{"POST", "/", "application/json", `{"query": "{greeting,notes(count: $count){text}}", "variables": {"count": 2}, "operationName": "Notes"}`, 200, `{"data":{"greeting":"Hello, John","notes":[{"text":"note"},{"text":"note"}]}}`},
+		{"POST", "/", "application/graphql", `{greeting}`, 200, `{"data":{"greeting":"Hello, John"}}`},
+		{"POST", "/", "", `{greeting}`, 200, `{"data":{"greeting":"Hello, John"}}`},
+		{"POST", "/", "text/plain; charset=utf-8", `{greeting,failing}`, 200, `{"data":{"greeting":"Hello, John","failing":null},"errors":[{"message":"failed","path":["failing"],"locations":[{"line":1,"column":11}]}]}`},
+		{"GET", "/?query=" + url.QueryEscape(`{notes(count: $c){text}}`) + "&variables=" + url.QueryEscape(`{"c": 1}`), "", "", 200, `{"data":{"notes":[{"text":"note"}]}}`},
+		{"GET", "/?variables=1", "", "", 400, `{"data":null,"errors":[{"message":"variables must be JSON object"}]}`},
+		{"GET", "/", "", "", 400, `{"data":null,"errors":[{"message":"query is required"}]}`},
+		{"POST", "/", "application/json", `{"query": "{notes(count: $count){text}}"}`, 400, `{"data":null,"errors":[{"message":"variable $count is not defined"}]}`},
+		{"POST", "/", "application/json", `{"query": 1}`, 400, `{"data":null,"errors":[{"message":"body must be JSON object with the query"}]}`},
+		{"POST", "/", "application/xml", `<query/>`, 415, `{"data":null,"errors":[{"message":"unsupported content type application/xml"}]}`},
+		{"POST", "/", "", strings.Repeat(" ", 300) + `{greeting}`, 413, `{"data":null,"errors":[{"message":"body is too large"}]}`},
+		{"PUT", "/", "", `{greeting}`, 405, `{"data":null,"errors":[{"message":"method PUT is not allowed"}]}`},
+	}
+
+	for _, c := range cases {
+		code, contentType, resp := request(t, c.method, server.URL+c.target, c.contentType, c.body)
+		if code != c.code || resp != c.resp || contentType != "application/json; charset=utf-8" {
+			t.Fatal("Not equal: ", code, resp)
+		}
+	}
+
+	// Context callback can reject the request
+	resp, err := http.Post(server.URL, "application/graphql", strings.NewReader(`{greeting}`))
+	if err != nil {
+		t.Fatal("Request error: " + err.Error())
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestHandlerWithoutPartialResults(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	code, _, resp := request(t, "POST", server.URL, "", `{greeting,failing}`)
+	if code != 200 || resp != `{"data":null,"errors":[{"message":"failed"}]}` {
+		t.Fatal("Not equal: ", code, resp)
+	}
+
+	// Untyped generator receives the empty context map
+	parser := hypeql.NewQueryParser(hypeql.QueryParserConfig{})
+	handler := NewHandler(Config[map[string]any]{
+		Parser:     parser,
+		Generator:  hypeql.NewResponseGenerator(hypeql.ResponseGeneratorConfig{}),
+		DataStruct: Note{Text: "static"},
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/", strings.NewReader(`{text}`)))
+	if recorder.Code != 200 || recorder.Body.String() != `{"data":{"text":"static"}}` {
+		t.Fatal("Not equal: " + recorder.Body.String())
+	}
+}
+
+func TestBatchedRequests(t *testing.T) {
+	generator, err := hypeql.NewTypedResponseGenerator[Session](hypeql.ResponseGeneratorConfig{
+		PartialResults: true,
+	}, Response{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	for _, concurrent := range []bool{false, true} {
+		handler := NewHandler(Config[*Session]{
+			Parser:            hypeql.NewQueryParser(hypeql.QueryParserConfig{}),
+			Generator:         generator,
+			DataStruct:        Response{},
+			MaxBatchSize:      3,
+			ConcurrentBatches: concurrent,
+			Context: func(w http.ResponseWriter, r *http.Request) (*Session, error) {
+				return &Session{User: "John"}, nil
+			},
+		})
+
+		cases := []struct {
+			body string
+			code int
+			resp string
+		}{
+			{
+				`[{"query": "{greeting}"}, {"query": "{notes(count: $c){text}}", "variables": {"c": 2}}, {"query": "{failing}"}]`,
+				200,
+				`[{"data":{"greeting":"Hello, John"}},{"data":{"notes":[{"text":"note"},{"text":"note"}]}},{"data":{"failing":null},"errors":[{"message":"failed","path":["failing"],"locations":[{"line":1,"column":2}]}]}]`,
+			},
+			{
+				` [{"query": "{notes(count: 1{text}}"}, {"query": ""}, {"query": "{notes(count: $c){text}}"}]`,
+				200,
+				`[{"data":null,"errors":[{"message":"the curly bracket is not closed"}]},{"data":null,"errors":[{"message":"query is required"}]},{"data":null,"errors":[{"message":"variable $c is not defined"}]}]`,
+			},
+			{`[]`, 400, `{"data":null,"errors":[{"message":"batch is empty"}]}`},
+			{`[{}, {}, {}, {}]`, 400, `{"data":null,"errors":[{"message":"batch has 4 requests, but the limit is 3"}]}`},
+			{`[1, 2`, 400, `{"data":null,"errors":[{"message":"body must be JSON array of objects with queries"}]}`},
+		}
+
+		for _, c := range cases {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", strings.NewReader(c.body))
+			req.Header.Set("Content-Type", "application/json")
+			handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != c.code || recorder.Body.String() != c.resp {
+				t.Fatal("Not equal: ", recorder.Code, recorder.Body.String())
+			}
+		}
+	}
+}
+
+func TestPersistedQueries(t *testing.T) {
+	generator, err := hypeql.NewTypedResponseGenerator[Session](hypeql.ResponseGeneratorConfig{}, Response{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	parser := hypeql.NewQueryParser(hypeql.QueryParserConfig{})
+	handler := NewHandler(Config[*Session]{
+		Parser:           parser,
+		Generator:        generator,
+		DataStruct:       Response{},
+		PersistedQueries: hypeql.NewMemoryQueryStore(parser),
+		RegisterQueries:  true,
+		Context: func(w http.ResponseWriter, r *http.Request) (*Session, error) {
+			return &Session{User: "John"}, nil
+		},
+	})
+
+	extensions := url.QueryEscape(`{"persistedQuery":{"version":1,"sha256Hash":"` + hypeql.QueryHash(`{greeting}`) + `"}}`)
+	cases := []struct {
+		method string
+		target string
+		body   string
+		code   int
+		resp   string
+	}{
+		{"GET", "/?extensions=" + extensions, "", 400, `{"data":null,"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`},
+		{"GET", "/?extensions=" + extensions + "&query=" + url.QueryEscape(`{greeting}`), "", 200, `{"data":{"greeting":"Hello, John"}}`},
+		{"GET", "/?extensions=" + extensions, "", 200, `{"data":{"greeting":"Hello, John"}}`},
+		{"POST", "/", `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hypeql.QueryHash(`{greeting}`) + `"}}}`, 200, `{"data":{"greeting":"Hello, John"}}`},
+		{"GET", "/?extensions=1", "", 400, `{"data":null,"errors":[{"message":"extensions must be JSON object"}]}`},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.code || recorder.Body.String() != c.resp {
+			t.Fatal("Not equal: ", recorder.Code, recorder.Body.String())
+		}
+	}
+}
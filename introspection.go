@@ -0,0 +1,215 @@
+package hypeql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Description of the response struct and its nested structs for tools (query explorers, code generators)
+type Schema struct {
+	Root  string        `json:"root"`  // Name of the response struct type
+	Types []*SchemaType `json:"types"` // Struct types in order of discovery (the first is the response struct)
+}
+
+// Struct type that can be requested with fields
+type SchemaType struct {
+	Name   string         `json:"name"`
+	Fields []*SchemaField `json:"fields"`
+}
+
+// Field that has the JSON tag
+type SchemaField struct {
+	Name          string            `json:"name"`                // Name from the JSON tag
+	Type          string            `json:"type"`                // Name of the struct type (or element type of the list) or the scalar (String, Int, Float, Boolean, Any or name of the custom scalar type)
+	List          bool              `json:"list"`                // True if the field is a slice
+	Object        bool              `json:"object"`              // True if the field is a struct or a list of structs (it's requested with fields)
+	Nullable      bool              `json:"nullable"`            // True if the value can be null (pointers, slices, maps, fields that can fail in the partial results mode)
+	NullableItems bool              `json:"nullableItems"`       // True if elements of the list can be null (pointers, objects whose "Resolve" method can fail)
+	Arguments     []*SchemaArgument `json:"arguments,omitempty"` // Arguments of the arguments struct that the resolver function receives
+}
+
+// Argument of a list field
+type SchemaArgument struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`              // Name of the scalar
+	Required bool        `json:"required"`          // Argument doesn't have a default value and isn't a pointer
+	Default  interface{} `json:"default,omitempty"` // Default value from the "default" tag
+}
+
+// Returns the struct type by name (nil if it isn't found)
+func (a *Schema) Type(name string) *SchemaType {
+	for _, t := range a.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Returns the field by name (nil if it isn't found)
+func (a *SchemaType) Field(name string) *SchemaField {
+	for _, f := range a.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// Describes the response struct and its nested structs
+func Introspect(dataStruct interface{}) (*Schema, error) {
+	return introspect(dataStruct, nil)
+}
+
+// Describes the response struct and its nested structs with custom scalar types of the generator
+func (a responseGenerator) Introspect(dataStruct interface{}) (*Schema, error) {
+	return introspect(dataStruct, a.Config.Scalars)
+}
+
+func introspect(dataStruct interface{}, scalars *ScalarRegistry) (*Schema, error) {
+	t := reflect.TypeOf(dataStruct)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dataStruct argument must be instance of struct or pointer to struct")
+	}
+
+	root := schemaTypeName(t)
+	if root == "" {
+		root = "Root"
+	}
+
+	schema := &Schema{
+		Root: root,
+	}
+
+	if err := introspectStruct(schema, t, scalars, map[reflect.Type]string{t: root}); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// Describes the struct type and its nested struct types (names has names of described types and types that will be described)
+func introspectStruct(schema *Schema, t reflect.Type, scalars *ScalarRegistry, names map[reflect.Type]string) error {
+	schemaType := &SchemaType{
+		Name:   names[t],
+		Fields: []*SchemaField{},
+	}
+	schema.Types = append(schema.Types, schemaType)
+
+	nested := []reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		name := sf.Tag.Get("json")
+		if name == "" || sf.Type.Kind() == reflect.Func {
+			continue
+		}
+
+		// Fields with resolver functions, the "auth" tag or custom scalar types become null if they fail in the partial results mode
+		field := &SchemaField{
+			Name:     name,
+			Nullable: isNullableKind(sf.Type.Kind()) || sf.Tag.Get("fun") != "" || sf.Tag.Get("auth") != "",
+		}
+
+		fieldType := sf.Type
+		if fieldType.Kind() == reflect.Slice {
+			field.List = true
+			fieldType = fieldType.Elem()
+			field.NullableItems = isNullableKind(fieldType.Kind())
+		}
+
+		// Structs of custom scalar types are written as values
+		if isObjectType(fieldType, scalars) {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			// Objects become null if their "Resolve" method fails
+			if _, ok := reflect.PointerTo(fieldType).MethodByName("Resolve"); ok {
+				if field.List {
+					field.NullableItems = true
+				} else {
+					field.Nullable = true
+				}
+			}
+
+			// Anonymous structs are named by the struct and the field
+			nestedName, ok := names[fieldType]
+			if !ok {
+				if nestedName = schemaTypeName(fieldType); nestedName == "" {
+					nestedName = schemaType.Name + sf.Name
+				}
+
+				names[fieldType] = nestedName
+				nested = append(nested, fieldType)
+			}
+
+			field.Type = nestedName
+			field.Object = true
+		} else {
+			field.Type = scalars.name(fieldType)
+			field.Nullable = field.Nullable || scalars.lookup(fieldType) != nil
+		}
+
+		// Arguments are known only if the resolver function receives the arguments struct
+		if method, ok := reflect.PointerTo(t).MethodByName(sf.Tag.Get("fun")); ok && method.Type.NumIn() == 3 && isArgumentsType(method.Type.In(2)) {
+			definitions, err := argumentDefinitions(method.Type.In(2), scalars)
+			if err != nil {
+				return err
+			}
+
+			for _, definition := range definitions {
+				field.Arguments = append(field.Arguments, &SchemaArgument{
+					Name:     definition.name,
+					Type:     scalars.name(definition.fieldType),
+					Required: definition.required,
+					Default:  definition.defaultVal,
+				})
+			}
+		}
+
+		// Lists with the "filter" tag have the "where" and "orderBy" arguments
+		if field.List && sf.Tag.Get("filter") == "true" {
+			field.Arguments = append(field.Arguments, &SchemaArgument{Name: "where", Type: "String"}, &SchemaArgument{Name: "orderBy", Type: "String"})
+		}
+
+		schemaType.Fields = append(schemaType.Fields, field)
+	}
+
+	for _, elem := range nested {
+		if err := introspectStruct(schema, elem, scalars, names); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Values of pointers, slices, maps and interfaces can be nil (written as null)
+func isNullableKind(kind reflect.Kind) bool {
+	return kind == reflect.Pointer || kind == reflect.Slice || kind == reflect.Map || kind == reflect.Interface
+}
+
+// Returns the name of the struct type without packages.
+// Names of generic types start with names of type arguments (Connection[Film] is FilmConnection)
+func schemaTypeName(t reflect.Type) string {
+	name := t.Name()
+
+	start := strings.Index(name, "[")
+	if start == -1 {
+		return name
+	}
+
+	prefix := ""
+	for _, arg := range strings.Split(name[start+1:len(name)-1], ",") {
+		prefix += strings.TrimLeft(arg[strings.LastIndex(arg, ".")+1:], "*[]")
+	}
+
+	return prefix + name[:start]
+}
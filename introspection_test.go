@@ -0,0 +1,59 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIntrospect(t *testing.T) {
+	schema, err := Introspect(&Store{})
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	out, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal("Marshal error: " + err.Error())
+	}
+
+	mustBe := `{"root":"Store","types":[` +
+		`{"name":"Store","fields":[{"name":"goods","type":"Good","list":true,"object":true,"nullable":true,"nullableItems":false,"arguments":[` +
+		`{"name":"category","type":"String","required":true},` +
+		`{"name":"page","type":"Int","required":false,"default":1},` +
+		`{"name":"limit","type":"Int","required":false},` +
+		`{"name":"maxPrice","type":"Float","required":false,"default":99.5},` +
+		`{"name":"inStock","type":"Boolean","required":false,"default":true},` +
+		`{"name":"note","type":"String","required":false}]}]},` +
+		`{"name":"Good","fields":[{"name":"name","type":"String","list":false,"object":false,"nullable":false,"nullableItems":false}]}]}`
+
+	if string(out) != mustBe {
+		t.Fatal("Not equal: " + string(out))
+	}
+
+	if schema.Type("Good").Field("name") == nil || schema.Type("Film") != nil {
+		t.Fatal("Not equal")
+	}
+
+	if _, err := Introspect(1); err == nil {
+		t.Fatal("Not equal")
+	}
+
+	// Anonymous structs are named by the struct and the field
+	var anonymous struct {
+		Meta struct {
+			Pages []*struct {
+				Number int `json:"number"`
+			} `json:"pages"`
+		} `json:"meta"`
+	}
+
+	schema, err = Introspect(anonymous)
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	pages := schema.Type("RootMeta").Field("pages")
+	if schema.Root != "Root" || schema.Type("Root").Field("meta").Type != "RootMeta" || pages.Type != "RootMetaPages" || !pages.Nullable || !pages.NullableItems || schema.Type("RootMetaPages") == nil {
+		t.Fatal("Not equal")
+	}
+}
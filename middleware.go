@@ -0,0 +1,68 @@
+package hypeql
+
+import "reflect"
+
+// Information about a call of resolver function or "Resolve" method that middlewares receive
+type ResolveInfo struct {
+	Path      []interface{}          // Path of the field (path of the object for "Resolve" methods)
+	Field     string                 // Field's tag name (empty for "Resolve" methods)
+	Method    string                 // Name of the called method
+	Parent    interface{}            // Object (struct) whose method is called
+	Arguments map[string]interface{} // Arguments of the list field (nil for basic fields and "Resolve" methods)
+	Fields    []string               // Needed fields of the object (only for "Resolve" methods)
+	Context   interface{}            // Context that the method receives (*map[string]interface{} or *C of the typed generator)
+}
+
+// Calls the next middleware or the method itself and returns its result
+type ResolveFunc func(info ResolveInfo) (interface{}, error)
+
+// Wraps calls of resolver functions and "Resolve" methods.
+// Middleware can change the info before calling next, and the result and the error after it
+type Middleware func(info ResolveInfo, next ResolveFunc) (interface{}, error)
+
+// Calls the method through middlewares, the first middleware of the config is the outermost one
+func (a responseGenerator) withMiddlewares(exec *execution, info ResolveInfo, call ResolveFunc) (interface{}, error) {
+	// Panics of the method are converted to errors, so middlewares receive them as errors
+	call = a.recovered(call)
+
+	// Timings are recorded by the innermost middleware
+	if exec.shared.tracer != nil {
+		call = exec.shared.tracer.wrap(call)
+	}
+
+	for i := len(a.Config.Middlewares) - 1; i >= 0; i-- {
+		middleware, next := a.Config.Middlewares[i], call
+		call = func(info ResolveInfo) (interface{}, error) {
+			return middleware(info, next)
+		}
+	}
+
+	return call(info)
+}
+
+// Converts values returned by a method to the result and the error
+func resolverResult(res []reflect.Value) (interface{}, error) {
+	if err := resolverError(res); err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 || res[0].Type() == reflect.TypeFor[error]() {
+		return nil, nil
+	}
+
+	return res[0].Interface(), nil
+}
+
+// Checks if the resolver function returned nothing (then the field's value is used).
+// Any non-nil value returned as an interface is not empty
+func emptyResult(result interface{}, t reflect.Type) bool {
+	if result == nil {
+		return true
+	}
+
+	if t.NumOut() > 0 && t.Out(0).Kind() == reflect.Interface {
+		return false
+	}
+
+	return reflect.ValueOf(result).IsZero()
+}
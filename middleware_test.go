@@ -0,0 +1,101 @@
+package hypeql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewares(t *testing.T) {
+	calls := []string{}
+
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Middlewares: []Middleware{
+			func(info ResolveInfo, next ResolveFunc) (any, error) {
+				calls = append(calls, "first "+info.Method+" "+pathString(info.Path))
+
+				result, err := next(info)
+				if err != nil {
+					return nil, fmt.Errorf("wrapped: %w", err)
+				}
+
+				return result, nil
+			},
+			func(info ResolveInfo, next ResolveFunc) (any, error) {
+				calls = append(calls, "second "+info.Method)
+
+				result, err := next(info)
+				if text, ok := result.(string); ok {
+					return strings.ToUpper(text), err
+				}
+
+				return result, err
+			},
+		},
+	})
+
+	resp, err := generator.Generate([]any{"bar", []any{"clist", []any{"text"}, map[string]any{"max": 1}}}, A{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"bar":true,"clist":[{"text":"HELLO"},{"text":"HELLO"}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	mustBe := []string{
+		"first Rbar bar", "second Rbar",
+		"first Rclist clist", "second Rclist",
+		"first Resolve clist.0", "second Resolve",
+		"first Rtext clist.0.text", "second Rtext",
+		"first Resolve clist.1", "second Resolve",
+		"first Rtext clist.1.text", "second Rtext",
+	}
+
+	if strings.Join(calls, "\n") != strings.Join(mustBe, "\n") {
+		t.Fatal("Not equal: ", calls)
+	}
+
+	_, err = generator.Generate([]any{"value"}, ThrowsError{}, map[string]any{})
+	if err == nil || err.Error() != "wrapped: Error throws here" {
+		t.Fatal("Not equal")
+	}
+}
+
+type Ledger struct {
+	Total int `json:"total" fun:"Rtotal"`
+}
+
+func (a Ledger) Rtotal(ctx *map[string]any) (int, error) {
+	return 0, &ResponseError{
+		Message:    "no total",
+		Path:       []interface{}{"elsewhere"},
+		Extensions: map[string]interface{}{"code": "NO_TOTAL"},
+	}
+}
+
+func TestMiddlewareWrappedResponseError(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		PartialResults: true,
+		Middlewares: []Middleware{
+			func(info ResolveInfo, next ResolveFunc) (any, error) {
+				result, err := next(info)
+				if err != nil {
+					return nil, fmt.Errorf("ledger: %w", err)
+				}
+
+				return result, nil
+			},
+		},
+	})
+
+	resp, err := generator.Generate([]any{"total"}, Ledger{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	// The message of the middleware and the path of the field are kept
+	if resp != `{"data":{"total":null},"errors":[{"message":"ledger: elsewhere: no total","path":["total"],"extensions":{"code":"NO_TOTAL"}}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+}
@@ -0,0 +1,102 @@
+package hypeql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Object of the response that keeps fields in the order they were requested in the query
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		keys:   []string{},
+		values: map[string]interface{}{},
+	}
+}
+
+// Sets the value of the key. New keys are added to the end, existing keys keep their position
+func (a *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := a.values[key]; !ok {
+		a.keys = append(a.keys, key)
+	}
+
+	a.values[key] = value
+}
+
+func (a *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := a.values[key]
+	return value, ok
+}
+
+// Returns keys in their order
+func (a *OrderedMap) Keys() []string {
+	return append([]string{}, a.keys...)
+}
+
+func (a *OrderedMap) Len() int {
+	return len(a.keys)
+}
+
+// Converts the object to the map (nested objects are converted too), use it if you don't need the order of fields
+func (a *OrderedMap) Map() map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, key := range a.keys {
+		ret[key] = unorderValue(a.values[key])
+	}
+
+	return ret
+}
+
+// Converts ordered objects in the value to maps
+func unorderValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *OrderedMap:
+		if v == nil {
+			return nil
+		}
+
+		return v.Map()
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, element := range v {
+			ret[i] = unorderValue(element)
+		}
+
+		return ret
+	}
+
+	return value
+}
+
+// Writes fields to JSON in their order
+func (a *OrderedMap) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+
+	for i, key := range a.keys {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		v, err := json.Marshal(a.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package hypeql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsOrder(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	parsed, err := parser.Parse("{clist{foo,text},foo,bar,foo}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(parsed, A{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	mustBe := `{"clist":[{"foo":"bar","text":"Hello"},{"foo":"bar","text":"Hello"}],"foo":"","bar":true}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+}
+
+func TestResultMap(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	parsed, err := parser.Parse("{bar,clist{text}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	result, err := generator.GenerateResult(parsed, A{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if !reflect.DeepEqual(result.Data.Keys(), []string{"bar", "clist"}) {
+		t.Fatal("Not equal")
+	}
+
+	mustBe := map[string]any{
+		"bar": true,
+		"clist": []any{
+			map[string]any{"text": "Hello"},
+			map[string]any{"text": "Hello"},
+		},
+	}
+
+	if !reflect.DeepEqual(result.Data.Map(), mustBe) {
+		t.Fatal("Not equal")
+	}
+}
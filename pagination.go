@@ -0,0 +1,183 @@
+package hypeql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// List of the cursor pagination (Relay-style connection)
+type Connection[T any] struct {
+	Edges      []Edge[T] `json:"edges"`
+	PageInfo   PageInfo  `json:"pageInfo"`
+	TotalCount int       `json:"totalCount"` // Count of all elements of the list (not only elements of the page)
+}
+
+// Element of the connection with its cursor
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// Information about the page of the connection
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"` // Cursor of the first edge (null if the page is empty)
+	EndCursor       *string `json:"endCursor"`   // Cursor of the last edge (null if the page is empty)
+}
+
+// Arguments of the connection field, resolver function can receive them or a struct that embeds them:
+//
+//	func (a Response) Rfilms(ctx *map[string]any, args hypeql.ConnectionArgs) (hypeql.Connection[Film], error)
+type ConnectionArgs struct {
+	First  *int    `arg:"first" min:"0"` // Count of elements after the "after" cursor
+	After  *string `arg:"after"`
+	Last   *int    `arg:"last" min:"0"` // Count of elements before the "before" cursor
+	Before *string `arg:"before"`
+}
+
+// Data source that loads pages itself (database, for example)
+type PageSource[T any] interface {
+	Count() (int, error)                     // Returns count of all elements
+	Load(offset int, limit int) ([]T, error) // Returns elements of the page
+}
+
+const cursorPrefix = "cursor:"
+
+// Returns the opaque cursor of the element at the offset
+func OffsetCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// Returns the offset of the element from the cursor created by "OffsetCursor"
+func CursorOffset(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor %s", cursor)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor %s", cursor)
+	}
+
+	return offset, nil
+}
+
+// Returns the page of the slice
+func ConnectionFromSlice[T any](items []T, args ConnectionArgs) (Connection[T], error) {
+	start, end, err := args.bounds(len(items))
+	if err != nil {
+		return Connection[T]{}, err
+	}
+
+	return newConnection(items[start:end], start, len(items)), nil
+}
+
+// Returns the page loaded from the source (the source is asked for count of elements and elements of the page only)
+func ConnectionFromSource[T any](source PageSource[T], args ConnectionArgs) (Connection[T], error) {
+	total, err := source.Count()
+	if err != nil {
+		return Connection[T]{}, err
+	}
+
+	start, end, err := args.bounds(total)
+	if err != nil {
+		return Connection[T]{}, err
+	}
+
+	items := []T{}
+	if end > start {
+		if items, err = source.Load(start, end-start); err != nil {
+			return Connection[T]{}, err
+		}
+	}
+
+	return newConnection(items, start, total), nil
+}
+
+// Returns offsets of the first element of the page and the element after the last one.
+// Cursors must point to elements of the list, so offsets are added without overflows
+func (a ConnectionArgs) bounds(total int) (int, int, error) {
+	start, end := 0, total
+
+	if a.After != nil {
+		offset, err := listCursorOffset(*a.After, total)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		start = offset + 1
+	}
+
+	if a.Before != nil {
+		offset, err := listCursorOffset(*a.Before, total)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		end = max(offset, start)
+	}
+
+	if a.First != nil {
+		if *a.First < 0 {
+			return 0, 0, fmt.Errorf("first must be at least 0")
+		}
+
+		if *a.First < end-start {
+			end = start + *a.First
+		}
+	}
+
+	if a.Last != nil {
+		if *a.Last < 0 {
+			return 0, 0, fmt.Errorf("last must be at least 0")
+		}
+
+		if *a.Last < end-start {
+			start = end - *a.Last
+		}
+	}
+
+	return start, end, nil
+}
+
+// Returns the offset of the cursor that points to an element of the list with the count of elements
+func listCursorOffset(cursor string, total int) (int, error) {
+	offset, err := CursorOffset(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	if offset >= total {
+		return 0, fmt.Errorf("invalid cursor %s", cursor)
+	}
+
+	return offset, nil
+}
+
+func newConnection[T any](items []T, start int, total int) Connection[T] {
+	connection := Connection[T]{
+		Edges:      make([]Edge[T], len(items)),
+		TotalCount: total,
+	}
+
+	for i, item := range items {
+		connection.Edges[i] = Edge[T]{
+			Node:   item,
+			Cursor: OffsetCursor(start + i),
+		}
+	}
+
+	if len(items) != 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(items)-1].Cursor
+	}
+
+	connection.PageInfo.HasPreviousPage = start > 0
+	connection.PageInfo.HasNextPage = start+len(items) < total
+
+	return connection
+}
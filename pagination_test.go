@@ -0,0 +1,155 @@
+package hypeql
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type Cinema struct {
+	Movies Connection[*Movie] `json:"movies" fun:"Rmovies"`
+}
+
+type MoviesArgs struct {
+	ConnectionArgs
+	Genre string `arg:"genre" default:"all"`
+}
+
+func (a Cinema) Rmovies(ctx *map[string]any, args MoviesArgs) (Connection[*Movie], error) {
+	movies := []*Movie{}
+	for _, title := range []string{"Alien", "Brazil", "Casablanca", "Dune", "Up"} {
+		movies = append(movies, &Movie{Title: title, Genre: args.Genre})
+	}
+
+	return ConnectionFromSlice(movies, args.ConnectionArgs)
+}
+
+type Movie struct {
+	Title string `json:"title"`
+	Genre string `json:"genre"`
+}
+
+type moviesSource struct {
+	loads [][2]int
+}
+
+func (a *moviesSource) Count() (int, error) {
+	return 100, nil
+}
+
+func (a *moviesSource) Load(offset int, limit int) ([]int, error) {
+	a.loads = append(a.loads, [2]int{offset, limit})
+
+	items := []int{}
+	for i := offset; i < offset+limit; i++ {
+		items = append(items, i)
+	}
+
+	return items, nil
+}
+
+func TestPagination(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+	parser := NewQueryParser(QueryParserConfig{})
+
+	body, err := parser.Parse(`{movies(first: 2, after: "` + OffsetCursor(0) + `", genre: "sci-fi"){totalCount,edges{cursor,node{title,genre}},pageInfo{hasNextPage,hasPreviousPage,endCursor}}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(body, Cinema{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	mustBe := `{"movies":{"totalCount":5,"edges":[` +
+		`{"cursor":"` + OffsetCursor(1) + `","node":{"title":"Brazil","genre":"sci-fi"}},` +
+		`{"cursor":"` + OffsetCursor(2) + `","node":{"title":"Casablanca","genre":"sci-fi"}}],` +
+		`"pageInfo":{"hasNextPage":true,"hasPreviousPage":true,"endCursor":"` + OffsetCursor(2) + `"}}}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	// Object fields are streamed the same way
+	stream := &strings.Builder{}
+	if err := generator.GenerateTo(stream, body, Cinema{}, map[string]any{}); err != nil || stream.String() != mustBe {
+		t.Fatal("Not equal: " + stream.String())
+	}
+
+	body, err = parser.Parse(`{movies(last: 2, before: "` + OffsetCursor(4) + `"){edges{node{title}}}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err = generator.Generate(body, Cinema{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"movies":{"edges":[{"node":{"title":"Casablanca"}},{"node":{"title":"Dune"}}]}}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	// Cursors out of the list and large counts don't overflow offsets
+	outside := []string{
+		`{movies(first: -1){totalCount}}`,
+		`{movies(after: "abc"){totalCount}}`,
+		`{movies(after: "` + base64.StdEncoding.EncodeToString([]byte("cursor:9223372036854775807")) + `"){totalCount}}`,
+		`{movies(after: "` + OffsetCursor(5) + `"){totalCount}}`,
+		`{movies(before: "` + OffsetCursor(5) + `"){totalCount}}`,
+		`{movies(after: "` + base64.StdEncoding.EncodeToString([]byte("cursor:-1")) + `"){totalCount}}`,
+	}
+
+	for _, query := range outside {
+		body, err = parser.Parse(query)
+		if err != nil {
+			t.Fatal("Parse error: " + err.Error())
+		}
+
+		if _, err := generator.Generate(body, Cinema{}, map[string]any{}); err == nil {
+			t.Fatal("Not equal")
+		}
+	}
+
+	body, err = parser.Parse(`{movies(first: ` + strconv.Itoa(math.MaxInt) + `, after: "` + OffsetCursor(3) + `"){edges{node{title}}}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err = generator.Generate(body, Cinema{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"movies":{"edges":[{"node":{"title":"Up"}}]}}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	last := math.MaxInt
+	if connection, err := ConnectionFromSlice([]int{1, 2, 3}, ConnectionArgs{Last: &last}); err != nil || len(connection.Edges) != 3 {
+		t.Fatal("Not equal")
+	}
+
+	schema, err := Introspect(Cinema{})
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	if schema.Type("Cinema").Field("movies").Type != "MovieConnection" || schema.Type("MovieEdge") == nil || schema.Type("PageInfo").Field("endCursor").Object {
+		t.Fatal("Not equal")
+	}
+
+	first, after := 3, OffsetCursor(97)
+	source := &moviesSource{}
+	connection, err := ConnectionFromSource[int](source, ConnectionArgs{First: &first, After: &after})
+	if err != nil {
+		t.Fatal("Pagination error: " + err.Error())
+	}
+
+	if len(connection.Edges) != 2 || connection.Edges[1].Node != 99 || connection.PageInfo.HasNextPage || len(source.loads) != 1 || source.loads[0] != [2]int{98, 2} {
+		t.Fatal("Not equal")
+	}
+}
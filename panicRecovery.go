@@ -0,0 +1,69 @@
+package hypeql
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Error of a resolver function or "Resolve" method that panicked
+type PanicError struct {
+	Path   string      // Path of the field (path of the object for "Resolve" methods)
+	Method string      // Name of the panicked method (empty for panics outside of resolvers, in the authorizer of the parallel mode, for example)
+	Value  interface{} // Value passed to panic
+	Stack  []byte      // Stack trace of the panic
+
+	debug bool // Stack trace is written to the response only in the debug mode
+}
+
+func (a *PanicError) Error() string {
+	subject := a.Method
+	if subject == "" {
+		subject = "field"
+	}
+
+	if a.Path == "" {
+		return fmt.Sprintf("%s panicked: %v", subject, a.Value)
+	}
+
+	return fmt.Sprintf("%s: %s panicked: %v", a.Path, subject, a.Value)
+}
+
+func (a *PanicError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{
+		"code": "INTERNAL_ERROR",
+	}
+
+	if a.debug {
+		ext["stacktrace"] = string(a.Stack)
+	}
+
+	return ext
+}
+
+// Wraps the call to convert its panic to the error (the panic handler of the config is called with it)
+func (a responseGenerator) recovered(call ResolveFunc) ResolveFunc {
+	return func(info ResolveInfo) (result interface{}, err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			panicErr := &PanicError{
+				Path:   pathString(info.Path),
+				Method: info.Method,
+				Value:  r,
+				Stack:  debug.Stack(),
+				debug:  a.Config.Debug,
+			}
+
+			if a.Config.PanicHandler != nil {
+				a.Config.PanicHandler(info, panicErr)
+			}
+
+			result, err = nil, panicErr
+		}()
+
+		return call(info)
+	}
+}
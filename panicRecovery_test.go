@@ -0,0 +1,111 @@
+package hypeql
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type Panicking struct {
+	Ok      string    `json:"ok"`
+	Crashes string    `json:"crashes" fun:"Rcrashes"`
+	Parts   []PartOne `json:"parts"`
+}
+
+type PartOne struct {
+	Id int `json:"id"`
+}
+
+func (a Panicking) Rcrashes(ctx *map[string]any) string {
+	var p *PartOne
+	return string(rune(p.Id))
+}
+
+func (a PartOne) Resolve(ctx *map[string]any, fields []string) {
+	panic("resolve panicked")
+}
+
+func TestPanicRecovery(t *testing.T) {
+	reported := []string{}
+
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		PartialResults: true,
+		Debug:          true,
+		PanicHandler: func(info ResolveInfo, err *PanicError) {
+			reported = append(reported, info.Method)
+		},
+	})
+
+	result, err := generator.GenerateResult([]any{"ok", "crashes", []any{"parts", []any{"id"}}}, Panicking{
+		Ok:    "yes",
+		Parts: []PartOne{{}},
+	}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if ok, _ := result.Data.Get("ok"); ok != "yes" {
+		t.Fatal("Not equal")
+	}
+
+	if len(result.Errors) != 2 || strings.Join(reported, ",") != "Rcrashes,Resolve" {
+		t.Fatal("Not equal")
+	}
+
+	if !strings.HasPrefix(result.Errors[0].Message, "crashes: Rcrashes panicked: runtime error: invalid memory address") {
+		t.Fatal("Not equal: " + result.Errors[0].Message)
+	}
+
+	if result.Errors[1].Message != "parts.0: Resolve panicked: resolve panicked" || pathString(result.Errors[1].Path) != "parts.0" {
+		t.Fatal("Not equal: " + result.Errors[1].Message)
+	}
+
+	if stack, _ := result.Errors[0].Extensions["stacktrace"].(string); !strings.Contains(stack, "Rcrashes") {
+		t.Fatal("No stack trace")
+	}
+
+	// Without the partial results mode the panic error is returned
+	_, err = NewResponseGenerator(ResponseGeneratorConfig{}).Generate([]any{"crashes"}, Panicking{}, map[string]any{})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Method != "Rcrashes" {
+		t.Fatal("Not equal")
+	}
+
+	if _, ok := panicErr.Extensions()["stacktrace"]; ok {
+		t.Fatal("Stack trace without the debug mode")
+	}
+}
+
+func TestLoaderPanic(t *testing.T) {
+	loader := NewLoader(func(keys []int) ([]int, error) {
+		panic("batch panicked")
+	})
+
+	if _, err := loader.Load(1).Get(); err == nil || err.Error() != "loader batch function panicked: batch panicked" {
+		t.Fatal("Not equal")
+	}
+}
+
+// Panics outside of resolvers don't crash goroutines of the parallel mode
+func TestParallelAuthorizerPanic(t *testing.T) {
+	var handled atomic.Int64
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Parallel: true,
+		Authorizer: AuthorizerFunc(func(req AuthRequest) error {
+			panic("no policy")
+		}),
+		PanicHandler: func(info ResolveInfo, err *PanicError) {
+			handled.Add(1)
+		},
+	})
+
+	staff := Staff{Employees: []Employee{{Name: "Bob"}, {Name: "Alice"}}}
+	_, err := generator.Generate([]any{[]any{"employees", []any{"name", "salary"}}}, staff, map[string]any{})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || err.Error() != "employees.0: field panicked: no policy" || handled.Load() == 0 {
+		t.Fatal("Not equal: ", err)
+	}
+}
@@ -0,0 +1,237 @@
+package hypeql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Extensions of the request: {"persistedQuery": {"version": 1, "sha256Hash": "..."}}
+type RequestExtensions struct {
+	PersistedQuery *PersistedQueryExtension `json:"persistedQuery,omitempty"`
+}
+
+// Hash of the persisted query sent instead of (or with) the query text
+type PersistedQueryExtension struct {
+	Version    int    `json:"version"`    // Only version 1 is supported
+	Sha256Hash string `json:"sha256Hash"` // Hex SHA-256 hash of the query text
+}
+
+// Persisted query with its parsed body (parsed bodies aren't changed by the executor, so they are shared between requests)
+type PersistedQuery struct {
+	Query string
+	Body  []interface{}
+}
+
+// Storage of persisted queries by their hashes
+type PersistedQueryStore interface {
+	Load(hash string) (*PersistedQuery, error) // Returns nil if there is no query with the hash
+	Save(hash string, query *PersistedQuery) error
+}
+
+// Errors of persisted queries (compare them with errors.Is, they are converted to response errors with the code in extensions)
+var (
+	ErrPersistedQueryNotFound     = &PersistedQueryError{message: "PersistedQueryNotFound", code: "PERSISTED_QUERY_NOT_FOUND"}
+	ErrPersistedQueryNotSupported = &PersistedQueryError{message: "PersistedQueryNotSupported", code: "PERSISTED_QUERY_NOT_SUPPORTED"}
+	ErrPersistedQueryNotAllowed   = &PersistedQueryError{message: "PersistedQueryNotAllowed", code: "PERSISTED_QUERY_NOT_ALLOWED"}
+)
+
+// Error of the persisted query, clients check its message
+type PersistedQueryError struct {
+	message string
+	code    string
+}
+
+func (a *PersistedQueryError) Error() string {
+	return a.message
+}
+
+func (a *PersistedQueryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": a.code,
+	}
+}
+
+// Returns the hex SHA-256 hash of the query text
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Store that keeps persisted queries in memory
+type MemoryQueryStore struct {
+	parser  Parser
+	mutex   sync.RWMutex
+	queries map[string]*PersistedQuery
+}
+
+// Creates the empty store, the parser parses queries added by "Add"
+func NewMemoryQueryStore(parser Parser) *MemoryQueryStore {
+	return &MemoryQueryStore{
+		parser:  parser,
+		queries: map[string]*PersistedQuery{},
+	}
+}
+
+// Parses the query and adds it to the store, returns its hash
+func (a *MemoryQueryStore) Add(query string) (string, error) {
+	body, err := a.parser.Parse(query)
+	if err != nil {
+		return "", err
+	}
+
+	hash := QueryHash(query)
+	return hash, a.Save(hash, &PersistedQuery{Query: query, Body: body})
+}
+
+func (a *MemoryQueryStore) Load(hash string) (*PersistedQuery, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.queries[hash], nil
+}
+
+func (a *MemoryQueryStore) Save(hash string, query *PersistedQuery) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.queries[hash] = query
+	return nil
+}
+
+// Store that reads queries from files of the directory (.hql, .graphql and .gql files of all subdirectories).
+// Saved queries are written to the "<hash>.hql" files
+type DirectoryQueryStore struct {
+	dir    string
+	memory *MemoryQueryStore
+}
+
+// Reads and parses all query files of the directory
+func NewDirectoryQueryStore(dir string, parser Parser) (*DirectoryQueryStore, error) {
+	store := &DirectoryQueryStore{
+		dir:    dir,
+		memory: NewMemoryQueryStore(parser),
+	}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".hql", ".graphql", ".gql":
+		default:
+			return nil
+		}
+
+		query, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if _, err := store.memory.Add(string(query)); err != nil {
+			return fmt.Errorf(path + ": " + err.Error())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (a *DirectoryQueryStore) Load(hash string) (*PersistedQuery, error) {
+	return a.memory.Load(hash)
+}
+
+func (a *DirectoryQueryStore) Save(hash string, query *PersistedQuery) error {
+	if err := os.WriteFile(filepath.Join(a.dir, hash+".hql"), []byte(query.Query), 0o644); err != nil {
+		return err
+	}
+
+	return a.memory.Save(hash, query)
+}
+
+// Returns the parsed body of the request and its query text: from the store of persisted queries or parsed by the parser
+func (a executor[C]) parseRequest(request Request) ([]interface{}, string, error) {
+	hash := ""
+	if request.Extensions != nil && request.Extensions.PersistedQuery != nil {
+		if request.Extensions.PersistedQuery.Version != 1 {
+			return nil, "", fmt.Errorf("unsupported persisted query version %d", request.Extensions.PersistedQuery.Version)
+		}
+
+		hash = request.Extensions.PersistedQuery.Sha256Hash
+	}
+
+	store := a.Config.PersistedQueries
+	if store == nil {
+		if hash != "" && request.Query == "" {
+			return nil, "", ErrPersistedQueryNotSupported
+		}
+
+		body, err := a.parse(request.Query)
+		return body, request.Query, err
+	}
+
+	if hash != "" && request.Query != "" && QueryHash(request.Query) != hash {
+		return nil, "", fmt.Errorf("hash of the persisted query doesn't match the query")
+	}
+
+	// Queries without hashes are looked up in the strict mode only (other queries are just parsed)
+	if hash == "" && a.Config.StrictPersistedQueries && request.Query != "" {
+		hash = QueryHash(request.Query)
+	}
+
+	if hash == "" {
+		body, err := a.parse(request.Query)
+		return body, request.Query, err
+	}
+
+	persisted, err := store.Load(hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if persisted != nil {
+		return persisted.Body, persisted.Query, nil
+	}
+
+	switch {
+	case a.Config.StrictPersistedQueries:
+		return nil, "", ErrPersistedQueryNotAllowed
+	case request.Query == "":
+		// The client sends the query with its hash again after this error
+		return nil, "", ErrPersistedQueryNotFound
+	}
+
+	body, err := a.parse(request.Query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if a.Config.RegisterQueries {
+		if err := store.Save(hash, &PersistedQuery{Query: request.Query, Body: body}); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return body, request.Query, nil
+}
+
+func (a executor[C]) parse(query string) ([]interface{}, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	return a.Config.Parser.Parse(query)
+}
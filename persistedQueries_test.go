@@ -0,0 +1,159 @@
+package hypeql
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type Motd struct {
+	Text   string `json:"text"`
+	Author string `json:"author"`
+}
+
+func TestPersistedQueries(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	store := NewMemoryQueryStore(parser)
+
+	hash, err := store.Add("{text}")
+	if err != nil {
+		t.Fatal("Store error: " + err.Error())
+	}
+
+	if hash != QueryHash("{text}") || len(hash) != 64 {
+		t.Fatal("Not equal: " + hash)
+	}
+
+	newExecutor := func(register bool, strict bool) executor[map[string]interface{}] {
+		return NewExecutor(ExecutorConfig[map[string]interface{}]{
+			Parser:                 parser,
+			Generator:              NewResponseGenerator(ResponseGeneratorConfig{}),
+			DataStruct:             Motd{Text: "Hello", Author: "John"},
+			PersistedQueries:       store,
+			RegisterQueries:        register,
+			StrictPersistedQueries: strict,
+		})
+	}
+
+	persisted := func(query string, hash string) Request {
+		return Request{
+			Query:      query,
+			Extensions: &RequestExtensions{PersistedQuery: &PersistedQueryExtension{Version: 1, Sha256Hash: hash}},
+		}
+	}
+
+	authorHash := QueryHash("{author}")
+	cases := []struct {
+		executor executor[map[string]interface{}]
+		request  Request
+		result   string
+		err      error
+	}{
+		{newExecutor(false, false), persisted("", hash), `{"text":"Hello"}`, nil},
+		{newExecutor(false, false), persisted("", authorHash), "", ErrPersistedQueryNotFound},
+		{newExecutor(false, false), persisted("{author}", authorHash), `{"author":"John"}`, nil},
+		{newExecutor(false, false), persisted("", authorHash), "", ErrPersistedQueryNotFound}, // Not registered
+		{newExecutor(false, false), Request{Query: "{author}"}, `{"author":"John"}`, nil},
+		{newExecutor(false, true), Request{Query: "{author}"}, "", ErrPersistedQueryNotAllowed},
+		{newExecutor(true, true), persisted("{author}", authorHash), "", ErrPersistedQueryNotAllowed},
+		{newExecutor(false, true), Request{Query: "{text}"}, `{"text":"Hello"}`, nil},
+		{newExecutor(true, false), persisted("{author}", authorHash), `{"author":"John"}`, nil},
+		{newExecutor(false, true), persisted("", authorHash), `{"author":"John"}`, nil}, // Registered
+		{newExecutor(false, false), persisted("{text}", authorHash), "", errors.New("hash of the persisted query doesn't match the query")},
+	}
+
+	for i, c := range cases {
+		result, err := c.executor.Execute(c.request, map[string]interface{}{})
+		if c.err != nil {
+			if err == nil || !errors.Is(err, c.err) && err.Error() != c.err.Error() {
+				t.Fatal("Not equal: ", i, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal("Execution error: ", i, err)
+		}
+
+		if out, _ := result.Data.MarshalJSON(); string(out) != c.result {
+			t.Fatal("Not equal: ", i, string(out))
+		}
+	}
+
+	// Hashes aren't accepted without the store
+	executor := NewExecutor(ExecutorConfig[map[string]interface{}]{
+		Parser:     parser,
+		Generator:  NewResponseGenerator(ResponseGeneratorConfig{}),
+		DataStruct: Motd{},
+	})
+
+	if _, err := executor.Execute(persisted("", hash), map[string]interface{}{}); !errors.Is(err, ErrPersistedQueryNotSupported) {
+		t.Fatal("Not equal")
+	}
+
+	// Every response error is a new value
+	first, second := NewResponseError(ErrPersistedQueryNotFound, nil), NewResponseError(ErrPersistedQueryNotFound, nil)
+	first.Extensions["code"] = "CHANGED"
+	if first == second || second.Extensions["code"] != "PERSISTED_QUERY_NOT_FOUND" || ErrPersistedQueryNotFound.Extensions()["code"] != "PERSISTED_QUERY_NOT_FOUND" {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestDirectoryQueryStore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "app"), 0o755); err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	files := map[string]string{
+		"app/motd.hql": "{text}",
+		"author.gql":   "{author}",
+		"readme.txt":   "{",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal("Setup error: " + err.Error())
+		}
+	}
+
+	parser := NewQueryParser(QueryParserConfig{})
+	store, err := NewDirectoryQueryStore(dir, parser)
+	if err != nil {
+		t.Fatal("Store error: " + err.Error())
+	}
+
+	for _, query := range []string{"{text}", "{author}"} {
+		if persisted, err := store.Load(QueryHash(query)); err != nil || persisted == nil || persisted.Query != query {
+			t.Fatal("Query isn't loaded: " + query)
+		}
+	}
+
+	if persisted, _ := store.Load(QueryHash("{")); persisted != nil {
+		t.Fatal("Not query file is loaded")
+	}
+
+	// Saved queries are loaded by new stores
+	hash := QueryHash("{text,author}")
+	if err := store.Save(hash, &PersistedQuery{Query: "{text,author}"}); err != nil {
+		t.Fatal("Store error: " + err.Error())
+	}
+
+	store, err = NewDirectoryQueryStore(dir, parser)
+	if err != nil {
+		t.Fatal("Store error: " + err.Error())
+	}
+
+	if persisted, _ := store.Load(hash); persisted == nil || len(persisted.Body) != 2 {
+		t.Fatal("Saved query isn't loaded")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.hql"), []byte("{text(a: 1{}"), 0o644); err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	if _, err := NewDirectoryQueryStore(dir, parser); err == nil {
+		t.Fatal("Invalid query is loaded")
+	}
+}
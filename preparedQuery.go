@@ -0,0 +1,259 @@
+package hypeql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Query prepared for the type of the data struct: fields, resolver methods and filters are found once, so executions don't search them.
+// It doesn't change after preparing, so it can be executed by many goroutines at once
+type preparedQuery[C any] struct {
+	generator  responseGenerator
+	dataStruct interface{}
+	plan       *objectPlan
+	variables  []string                // Names of variables used in arguments
+	context    func(ctx C) interface{} // Converts the context to the pointer that resolvers receive
+}
+
+// Selected fields of objects of the same type
+type objectPlan struct {
+	receiverType reflect.Type // Type of the value whose methods are called (see "receiverValue"), the plan is used only for objects of this type
+	resolve      int          // Index of the "Resolve" method (-1 if the type doesn't have it)
+	selections   []selection
+	keys         []string // Tag names of selected fields for the "Resolve" method
+}
+
+// Struct field of the selection
+type fieldPlan struct {
+	field     reflect.StructField
+	funcName  string                 // Name of the resolver function from the "fun" tag
+	method    int                    // Index of the resolver method (-1 if the type doesn't have it)
+	variables bool                   // Arguments have variables, so the filter and arguments are read while executing
+	filter    *listFilter            // Filter of the list field parsed while preparing
+	arguments map[string]interface{} // Arguments of the resolver function (without "where" and "orderBy" of the filter)
+}
+
+// Prepares the request body (with variables as "$name" arguments) for the data struct, the prepared query receives context maps.
+// Unknown fields and invalid filters are returned as errors
+func (a responseGenerator) Prepare(requestBody []interface{}, dataStruct interface{}) (preparedQuery[map[string]interface{}], error) {
+	plan, variables, err := a.prepare(requestBody, dataStruct)
+	if err != nil {
+		return preparedQuery[map[string]interface{}]{}, err
+	}
+
+	return preparedQuery[map[string]interface{}]{
+		generator:  a,
+		dataStruct: dataStruct,
+		plan:       plan,
+		variables:  variables,
+		context: func(ctx map[string]interface{}) interface{} {
+			return &ctx
+		},
+	}, nil
+}
+
+// Prepares the request body (with variables as "$name" arguments) for the data struct, the prepared query receives typed contexts
+func (a typedResponseGenerator[C]) Prepare(requestBody []interface{}, dataStruct interface{}) (preparedQuery[*C], error) {
+	plan, variables, err := a.generator.prepare(requestBody, dataStruct)
+	if err != nil {
+		return preparedQuery[*C]{}, err
+	}
+
+	return preparedQuery[*C]{
+		generator:  a.generator,
+		dataStruct: dataStruct,
+		plan:       plan,
+		variables:  variables,
+		context: func(ctx *C) interface{} {
+			return ctx
+		},
+	}, nil
+}
+
+func (a responseGenerator) prepare(requestBody []interface{}, dataStruct interface{}) (*objectPlan, []string, error) {
+	if !isObject(dataStruct) {
+		return nil, nil, fmt.Errorf("dataStruct argument must be instance of struct or pointer to struct")
+	}
+
+	plan, err := a.prepareObject(requestBody, reflect.TypeOf(dataStruct), []interface{}{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variables := []string{}
+	collectVariables(requestBody, &variables)
+	slices.Sort(variables)
+
+	return plan, slices.Compact(variables), nil
+}
+
+// Finds selected fields of the object type (struct or pointer to struct) and prepares objects of list fields
+func (a responseGenerator) prepareObject(fields []interface{}, t reflect.Type, path []interface{}) (*objectPlan, error) {
+	selections, err := parseSelections(fields, path)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &objectPlan{
+		receiverType: receiverType(t),
+		resolve:      -1,
+		selections:   selections,
+		keys:         selectionKeys(selections),
+	}
+
+	if method, ok := plan.receiverType.MethodByName("Resolve"); ok {
+		plan.resolve = method.Index
+	}
+
+	structType := t
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	for i := range plan.selections {
+		sel := &plan.selections[i]
+		fieldPath := appendPath(path, sel.key)
+
+		matches := isValueField
+		if sel.list {
+			matches = isListField
+		}
+
+		sf, ok := findStructField(structType, sel.key, a.Config.Scalars, matches)
+		if !ok {
+			return nil, fmt.Errorf(pathString(fieldPath) + " not found in the struct")
+		}
+
+		field := &fieldPlan{
+			field:    sf,
+			funcName: sf.Tag.Get("fun"),
+			method:   -1,
+		}
+
+		// Type of values returned by the resolver function
+		var resolverType reflect.Type
+		if field.funcName != "" {
+			if method, ok := plan.receiverType.MethodByName(field.funcName); ok {
+				field.method = method.Index
+				if method.Type.NumOut() != 0 {
+					resolverType = method.Type.Out(0)
+				}
+			}
+		}
+
+		if sel.list {
+			for _, value := range sel.arguments {
+				if isVariable(value) {
+					field.variables = true
+				}
+			}
+
+			if !field.variables {
+				if field.filter, field.arguments, err = parseListFilter(sf, sel.arguments, fieldPath); err != nil {
+					return nil, err
+				}
+			}
+
+			// Objects have the type returned by the resolver function (if it isn't an interface) or the type of the field
+			objectType := sf.Type
+			if resolverType != nil && (resolverType.Kind() == reflect.Slice || isObjectType(resolverType, a.Config.Scalars)) {
+				objectType = resolverType
+			}
+
+			if objectType.Kind() == reflect.Slice {
+				objectType = objectType.Elem()
+			}
+
+			// Objects of interface types are processed without the plan
+			if isObjectType(objectType, a.Config.Scalars) {
+				if sel.children, err = a.prepareObject(sel.fields, objectType, fieldPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		sel.plan = field
+	}
+
+	return plan, nil
+}
+
+// Finds the field of the struct type by the tag
+func findStructField(structType reflect.Type, key string, scalars *ScalarRegistry, matches func(sf reflect.StructField, scalars *ScalarRegistry) bool) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		if sf.Tag.Get("json") == key && matches(sf, scalars) {
+			return sf, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// Returns the type of values returned by "receiverValue" for values of the type
+func receiverType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Struct && reflect.PointerTo(t).NumMethod() > t.NumMethod() {
+		return reflect.PointerTo(t)
+	}
+
+	return t
+}
+
+// Adds names of variables used in arguments of the request body
+func collectVariables(requestBody []interface{}, variables *[]string) {
+	for _, item := range requestBody {
+		branch, ok := item.([]interface{})
+		if !ok || len(branch) < 2 {
+			continue
+		}
+
+		if fields, ok := branch[1].([]interface{}); ok {
+			collectVariables(fields, variables)
+		}
+
+		if len(branch) > 2 {
+			arguments, _ := branch[2].(map[string]interface{})
+			for _, value := range arguments {
+				if isVariable(value) {
+					*variables = append(*variables, string(value.(Variable)))
+				}
+			}
+		}
+	}
+}
+
+// Processes the prepared query with the context and values of variables
+func (a preparedQuery[C]) Execute(ctx C, variables map[string]interface{}) (*Result, error) {
+	if a.plan == nil {
+		return nil, fmt.Errorf("query isn't prepared")
+	}
+
+	// Variables are converted once for all arguments
+	converted := make(map[string]interface{}, len(a.variables))
+	for _, name := range a.variables {
+		variable, ok := variables[name]
+		if !ok {
+			return nil, fmt.Errorf("variable $" + name + " is not defined")
+		}
+
+		if variable == nil {
+			converted[name] = nil
+			continue
+		}
+
+		value, err := variableValue(variable)
+		if err != nil {
+			return nil, fmt.Errorf("variable $" + name + " " + err.Error())
+		}
+
+		converted[name] = value
+	}
+
+	exec := a.generator.newExecution()
+	exec.shared.variables = converted
+
+	return a.generator.run(exec, func(exec *execution) (interface{}, error) {
+		return a.generator.generatePlanned(a.plan, a.dataStruct, a.context(ctx), []interface{}{}, 1, exec)
+	})
+}
@@ -0,0 +1,201 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type Blog struct {
+	Title   string        `json:"title"`
+	Posts   []BlogPost    `json:"posts" fun:"Rposts" filter:"true"`
+	Authors []interface{} `json:"authors" fun:"Rauthors"`
+}
+
+type BlogPostsArgs struct {
+	Limit int `arg:"limit" default:"10"`
+}
+
+func (a Blog) Rposts(ctx *map[string]interface{}, args BlogPostsArgs) []BlogPost {
+	posts := []BlogPost{{Id: 3, Title: "Go"}, {Id: 1, Title: "Rust"}, {Id: 2, Title: "Zig"}}
+	return posts[:min(args.Limit, len(posts))]
+}
+
+func (a Blog) Rauthors(ctx *map[string]interface{}) []interface{} {
+	return []interface{}{BlogAuthor{Name: "John"}}
+}
+
+type BlogPost struct {
+	Id     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author" fun:"Rauthor"`
+}
+
+// Pointer receiver, so posts are processed as pointers
+func (a *BlogPost) Resolve(ctx *map[string]interface{}, fields []string) error {
+	if a.Id == 0 {
+		return errors.New("post isn't found")
+	}
+
+	(*ctx)["visited"] = a.Id
+	return nil
+}
+
+func (a *BlogPost) Rauthor(ctx *map[string]interface{}) string {
+	return "author of " + a.Title
+}
+
+type BlogAuthor struct {
+	Name  string     `json:"name"`
+	Posts []BlogPost `json:"posts"`
+}
+
+func TestPreparedQuery(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	query := `{
+		title
+		posts(limit: $limit, where: $where, orderBy: "id desc"){
+			id
+			author
+		}
+		authors{
+			name
+			posts{
+				id
+			}
+		}
+	}`
+
+	parsed, err := parser.Parse(query)
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	prepared, err := generator.Prepare(parsed, Blog{Title: "Blog"})
+	if err != nil {
+		t.Fatal("Preparing error: " + err.Error())
+	}
+
+	cases := []map[string]interface{}{
+		{"limit": 2, "where": "id gte 2"},
+		{"limit": float64(3), "where": nil},
+	}
+
+	for _, variables := range cases {
+		result, err := prepared.Execute(map[string]interface{}{}, variables)
+		if err != nil {
+			t.Fatal("Execution error: " + err.Error())
+		}
+
+		body, err := ApplyVariables(parsed, variables)
+		if err != nil {
+			t.Fatal("Variables error: " + err.Error())
+		}
+
+		expected, err := generator.Generate(body, Blog{Title: "Blog"}, map[string]interface{}{})
+		if err != nil {
+			t.Fatal("Generation error: " + err.Error())
+		}
+
+		if out, _ := json.Marshal(result.Data); string(out) != expected {
+			t.Fatal("Not equal: " + string(out) + " " + expected)
+		}
+	}
+
+	if _, err := prepared.Execute(map[string]interface{}{}, map[string]interface{}{"limit": 1}); err == nil || err.Error() != "variable $where is not defined" {
+		t.Fatal("Undefined variable is accepted")
+	}
+
+	if _, err := prepared.Execute(map[string]interface{}{}, map[string]interface{}{"limit": []int{}, "where": nil}); err == nil {
+		t.Fatal("Invalid variable is accepted")
+	}
+
+	invalid := []struct {
+		query string
+		err   string
+	}{
+		{`{posts{name}}`, "posts.name not found in the struct"},
+		{`{posts(orderBy: "name"){id}}`, "posts: argument orderBy is invalid: unknown field name"},
+		{`{title{id}}`, "title not found in the struct"},
+	}
+
+	for _, c := range invalid {
+		parsed, err := parser.Parse(c.query)
+		if err != nil {
+			t.Fatal("Parsing error: " + err.Error())
+		}
+
+		if _, err := generator.Prepare(parsed, Blog{}); err == nil || err.Error() != c.err {
+			t.Fatal("Not equal: ", err)
+		}
+	}
+}
+
+func TestPreparedQueryConcurrency(t *testing.T) {
+	generator, err := NewTypedResponseGenerator[Session](ResponseGeneratorConfig{
+		Parallel: true,
+	}, Account{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	parsed, err := NewQueryParser(QueryParserConfig{}).Parse(`{name}`)
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	prepared, err := generator.Prepare(parsed, Account{})
+	if err != nil {
+		t.Fatal("Preparing error: " + err.Error())
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result, err := prepared.Execute(&Session{User: strconv.Itoa(i)}, nil)
+			if err == nil {
+				out, _ := json.Marshal(result.Data)
+				results[i] = string(out)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i, result := range results {
+		expected, _ := generator.Generate(parsed, Account{}, &Session{User: strconv.Itoa(i)})
+		if result != expected {
+			t.Fatal("Not equal: " + result + " " + expected)
+		}
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+	variables := map[string]interface{}{"limit": 3, "where": "id gte 2"}
+
+	for i := 0; i < b.N; i++ {
+		parsed, _ := parser.Parse(`{title,posts(limit: $limit, where: $where){id,title,author}}`)
+		body, _ := ApplyVariables(parsed, variables)
+		generator.GenerateResult(body, Blog{}, map[string]interface{}{})
+	}
+}
+
+func BenchmarkPreparedQuery(b *testing.B) {
+	parsed, _ := NewQueryParser(QueryParserConfig{}).Parse(`{title,posts(limit: $limit, where: $where){id,title,author}}`)
+	prepared, _ := NewResponseGenerator(ResponseGeneratorConfig{}).Prepare(parsed, Blog{})
+	variables := map[string]interface{}{"limit": 3, "where": "id gte 2"}
+
+	for i := 0; i < b.N; i++ {
+		prepared.Execute(map[string]interface{}{}, variables)
+	}
+}
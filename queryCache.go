@@ -0,0 +1,179 @@
+package hypeql
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// Parser that keeps recently parsed queries (least recently used queries are removed first).
+// Every call returns a copy of the cached query, so callers can change it
+type QueryCache struct {
+	Config QueryCacheConfig
+
+	parser  Parser
+	mutex   sync.Mutex
+	order   *list.List // Elements are *cachedQuery, the most recently used is the first
+	entries map[string]*list.Element
+	stats   QueryCacheStats
+}
+
+type QueryCacheConfig struct {
+	MaxEntries uint64 // Limits count of cached queries, stay 0 if unlimited
+	MaxBytes   uint64 // Limits total length of cached query texts, stay 0 if unlimited
+	Normalize  bool   // Queries that differ only in tabs, indentation and empty lines share the cached result (the first of them is parsed)
+}
+
+// Statistics of the cache
+type QueryCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   uint64
+	Bytes     uint64
+}
+
+type cachedQuery struct {
+	key  string
+	body []interface{}
+}
+
+// Creates the cache in front of the parser (created by NewQueryParser)
+func NewQueryCache(parser Parser, config QueryCacheConfig) *QueryCache {
+	return &QueryCache{
+		Config:  config,
+		parser:  parser,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Returns the cached parsed query or parses it and adds it to the cache (queries with errors aren't cached)
+func (a *QueryCache) Parse(body string) ([]interface{}, error) {
+	key := body
+	if a.Config.Normalize {
+		key = normalizeQuery(body)
+	}
+
+	a.mutex.Lock()
+	if element, ok := a.entries[key]; ok {
+		a.order.MoveToFront(element)
+		a.stats.Hits++
+		a.mutex.Unlock()
+
+		return copyBody(element.Value.(*cachedQuery).body), nil
+	}
+	a.stats.Misses++
+	a.mutex.Unlock()
+
+	// Parsing without the lock, so other queries aren't blocked.
+	// The normalized query is only the key, the parser receives the query as it is
+	parsed, err := a.parser.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	a.add(key, parsed)
+
+	return copyBody(parsed), nil
+}
+
+// Returns a deep copy of the parsed query (nested fields and arguments maps are copied)
+func copyBody(body []interface{}) []interface{} {
+	result := make([]interface{}, len(body))
+
+	for i, item := range body {
+		branch, ok := item.([]interface{})
+		if !ok {
+			result[i] = item
+			continue
+		}
+
+		newBranch := make([]interface{}, len(branch))
+		for j, element := range branch {
+			switch element := element.(type) {
+			case []interface{}:
+				newBranch[j] = copyBody(element)
+			case map[string]interface{}:
+				arguments := make(map[string]interface{}, len(element))
+				for name, value := range element {
+					arguments[name] = value
+				}
+				newBranch[j] = arguments
+			default:
+				newBranch[j] = element
+			}
+		}
+
+		result[i] = newBranch
+	}
+
+	return result
+}
+
+func (a *QueryCache) add(key string, body []interface{}) {
+	size := uint64(len(key))
+	if a.Config.MaxBytes != 0 && size > a.Config.MaxBytes {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// The query was added by another goroutine while it was parsed
+	if _, ok := a.entries[key]; ok {
+		return
+	}
+
+	a.entries[key] = a.order.PushFront(&cachedQuery{key: key, body: body})
+	a.stats.Entries++
+	a.stats.Bytes += size
+
+	for a.Config.MaxEntries != 0 && a.stats.Entries > a.Config.MaxEntries || a.Config.MaxBytes != 0 && a.stats.Bytes > a.Config.MaxBytes {
+		a.removeOldest()
+	}
+}
+
+func (a *QueryCache) removeOldest() {
+	element := a.order.Back()
+	entry := a.order.Remove(element).(*cachedQuery)
+	delete(a.entries, entry.key)
+
+	a.stats.Entries--
+	a.stats.Bytes -= uint64(len(entry.key))
+	a.stats.Evictions++
+}
+
+// Returns statistics of the cache
+func (a *QueryCache) Stats() QueryCacheStats {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.stats
+}
+
+// Removes all cached queries (statistics of hits and misses are kept)
+func (a *QueryCache) Clear() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.order.Init()
+	a.entries = map[string]*list.Element{}
+	a.stats.Entries = 0
+	a.stats.Bytes = 0
+}
+
+// Removes tabs (the parser ignores them), spaces at the beginning and the end of lines and empty lines.
+// Lines can't be broken inside arguments, so values of arguments aren't changed
+func normalizeQuery(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\t", ""), "\n")
+
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.Trim(line, " "); line != "" {
+			result = append(result, line)
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
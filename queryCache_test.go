@@ -0,0 +1,141 @@
+package hypeql
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type Inbox struct {
+	Messages []Message `json:"messages" fun:"Rmessages"`
+}
+
+// Changes the arguments map to check that the cached query isn't changed
+func (a Inbox) Rmessages(ctx *map[string]interface{}, args map[string]interface{}) []Message {
+	limit, _ := args["limit"].(int)
+	args["limit"] = limit + 1
+
+	return make([]Message, limit)
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type countingParser struct {
+	parser Parser
+	calls  int
+	last   string // The last parsed query
+}
+
+func (a *countingParser) Parse(body string) ([]interface{}, error) {
+	a.calls++
+	a.last = body
+	return a.parser.Parse(body)
+}
+
+func TestQueryCache(t *testing.T) {
+	parser := &countingParser{parser: NewQueryParser(QueryParserConfig{})}
+	cache := NewQueryCache(parser, QueryCacheConfig{
+		MaxEntries: 2,
+		Normalize:  true,
+	})
+
+	first, err := cache.Parse("{\n\tmessages(limit: 2){\n\t\ttext\n\t}\n}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	second, err := cache.Parse("{\n  messages(limit: 2){\n    text\n  }\n\n}\n")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	// The parser receives the original query, not the normalized key
+	expected, _ := NewQueryParser(QueryParserConfig{}).Parse("{\n\tmessages(limit: 2){\n\t\ttext\n\t}\n}")
+	if !reflect.DeepEqual(first, expected) || !reflect.DeepEqual(second, expected) || parser.calls != 1 || parser.last != "{\n\tmessages(limit: 2){\n\t\ttext\n\t}\n}" {
+		t.Fatal("Not equal")
+	}
+
+	// Changes of the returned query don't change the cached one
+	first[0].([]interface{})[2].(map[string]interface{})["limit"] = 5
+	first[0].([]interface{})[1].([]interface{})[0] = "id"
+	third, _ := cache.Parse("{\n\tmessages(limit: 2){\n\t\ttext\n\t}\n}")
+	if !reflect.DeepEqual(second, expected) || !reflect.DeepEqual(third, expected) {
+		t.Fatal("Not equal: ", third)
+	}
+
+	// Queries with errors aren't cached
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Parse("{messages(limit: 1{}}"); err == nil {
+			t.Fatal("Invalid query is parsed")
+		}
+	}
+
+	if stats := cache.Stats(); stats != (QueryCacheStats{Hits: 2, Misses: 3, Entries: 1, Bytes: uint64(len(normalizeQuery("{\n\tmessages(limit: 2){\n\t\ttext\n\t}\n}")))}) {
+		t.Fatal("Not equal: ", stats)
+	}
+
+	// The least recently used query is removed
+	for _, query := range []string{"{a}", "{messages(limit: 2){text}}", "{b}"} {
+		if _, err := cache.Parse(query); err != nil {
+			t.Fatal("Parsing error: " + err.Error())
+		}
+	}
+
+	calls := parser.calls
+	cache.Parse("{b}")
+	if _, err := cache.Parse("{a}"); err != nil || parser.calls != calls+1 || cache.Stats().Evictions != 3 {
+		t.Fatal("Not equal: ", cache.Stats())
+	}
+
+	cache.Clear()
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Fatal("Cache isn't cleared")
+	}
+
+	// Queries longer than the limit aren't cached
+	small := NewQueryCache(parser, QueryCacheConfig{MaxBytes: 10})
+	small.Parse("{a}")
+	small.Parse("{a,b,c,d,e}")
+	small.Parse("{a,b,c}")
+	small.Parse("{b}")
+	if stats := small.Stats(); stats.Entries != 2 || stats.Bytes != 10 || stats.Evictions != 1 {
+		t.Fatal("Not equal: ", stats)
+	}
+}
+
+func TestQueryCacheSharedResults(t *testing.T) {
+	cache := NewQueryCache(NewQueryParser(QueryParserConfig{}), QueryCacheConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			parsed, err := cache.Parse("{messages(limit: 2){text}}")
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			resp, err := generator.Generate(parsed, Inbox{}, map[string]interface{}{})
+			if err != nil {
+				errs <- err
+			} else if resp != `{"messages":[{"text":""},{"text":""}]}` {
+				errs <- errors.New("Not equal: " + resp)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err.Error())
+	}
+}
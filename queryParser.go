@@ -143,6 +143,11 @@ func (a queryParser) parseRecursion(body string, deep uint64) ([]interface{}, er
 									return a
 								}
 
+								// Variables are written without quotes
+								if len(a) > 1 && a[0] == '$' {
+									return Variable(a[1:])
+								}
+
 								if i, err := strconv.Atoi(a); err == nil {
 									return i
 								}
@@ -182,12 +187,17 @@ func (a queryParser) parseRecursion(body string, deep uint64) ([]interface{}, er
 								value = value[1:]
 							}
 
-							// Two aviables types in the args: int, string
+							// Three aviables types in the args: int, string, variable
 							vars[key] = func(a string) any {
 								if varIsString {
 									return a
 								}
 
+								// Variables are written without quotes
+								if len(a) > 1 && a[0] == '$' {
+									return Variable(a[1:])
+								}
+
 								if i, err := strconv.Atoi(a); err == nil {
 									return i
 								}
@@ -278,3 +288,86 @@ func (a queryParser) parseRecursion(body string, deep uint64) ([]interface{}, er
 func cleanUp(a string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(a, " ", ""), "\t", ""), "\n", "")
 }
+
+// Returns positions of fields in the query text by their paths (names of fields joined with dots, positions of repeated fields are the first ones).
+// The text is scanned like "Parse" reads it: "#" starts the comment, arguments in parentheses are skipped
+func fieldLocations(body string) map[string]ErrorLocation {
+	locations := map[string]ErrorLocation{}
+	parents := []string{}
+	name := ""
+	location := ErrorLocation{}
+	last := "" // Name of the field that can be opened by the curly bracket
+	line, column := 1, 0
+	comment, arguments, quoteOpened, valueSlash := false, false, false, false
+
+	finishName := func() {
+		if name == "" {
+			return
+		}
+
+		path := strings.Join(append(append([]string{}, parents...), name), ".")
+		if _, ok := locations[path]; !ok {
+			locations[path] = location
+		}
+
+		last = name
+		name = ""
+	}
+
+	for _, c := range body {
+		if c == '\n' {
+			line++
+			column = 0
+		} else {
+			column++
+		}
+
+		switch {
+		case comment:
+			comment = c != '\n'
+		case arguments:
+			switch {
+			case valueSlash:
+				valueSlash = false
+			case quoteOpened && c == '\\':
+				valueSlash = true
+			case c == '"':
+				quoteOpened = !quoteOpened
+			case !quoteOpened && c == ')':
+				arguments = false
+			}
+		default:
+			switch c {
+			case '#':
+				finishName()
+				comment = true
+			case '(':
+				finishName()
+				arguments = true
+			case '{':
+				finishName()
+				// The curly bracket of the root doesn't follow a field
+				if last != "" {
+					parents = append(parents, last)
+					last = ""
+				}
+			case '}':
+				finishName()
+				last = ""
+				if len(parents) != 0 {
+					parents = parents[:len(parents)-1]
+				}
+			case ' ', '\t', '\r', '\n', ',':
+				finishName()
+			default:
+				if name == "" {
+					location = ErrorLocation{Line: line, Column: column}
+					last = ""
+				}
+				name += string(c)
+			}
+		}
+	}
+
+	return locations
+}
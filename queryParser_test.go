@@ -125,3 +125,26 @@ func TestDeepRecursionLimitParser(t *testing.T) {
 		t.Fatal("Not equal")
 	}
 }
+
+func TestFieldLocations(t *testing.T) {
+	locations := fieldLocations("{\n\ttitle # comment {x}\n\tposts(where: \"a) {b}\", limit: 2){\n\t\tid,author{\n\t\t\tname}\n\t}\n\tcount\n}")
+
+	expected := map[string]ErrorLocation{
+		"title":             {Line: 2, Column: 2},
+		"posts":             {Line: 3, Column: 2},
+		"posts.id":          {Line: 4, Column: 3},
+		"posts.author":      {Line: 4, Column: 6},
+		"posts.author.name": {Line: 5, Column: 4},
+		"count":             {Line: 7, Column: 2},
+	}
+
+	if len(locations) != len(expected) {
+		t.Fatal("Not equal: ", locations)
+	}
+
+	for path, location := range expected {
+		if locations[path] != location {
+			t.Fatal("Not equal: " + path)
+		}
+	}
+}
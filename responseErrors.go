@@ -0,0 +1,104 @@
+package hypeql
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Error of a single field that is written to the "errors" array of the response in the partial results mode
+type ResponseError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`      // Field names and list indexes from the root to the failed field
+	Locations  []ErrorLocation        `json:"locations,omitempty"` // Positions in the query text (empty when unknown)
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Position of a field in the query text
+type ErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (a *ResponseError) Error() string {
+	if len(a.Path) == 0 {
+		return a.Message
+	}
+
+	return pathString(a.Path) + ": " + a.Message
+}
+
+// Errors returned by resolvers can implement this interface to fill the "extensions" object of the response error
+type ErrorExtensions interface {
+	Extensions() map[string]interface{}
+}
+
+// Converts any error to the response error located at the path (errors that are *ResponseError are returned as they are).
+// Errors that wrap the response error keep its extensions, but they have their own message and the path
+func NewResponseError(err error, path []interface{}) *ResponseError {
+	if respErr, ok := err.(*ResponseError); ok {
+		return respErr
+	}
+
+	respErr := &ResponseError{
+		Message: err.Error(),
+		Path:    append([]interface{}{}, path...),
+	}
+
+	var ext ErrorExtensions
+	var wrapped *ResponseError
+	if errors.As(err, &ext) {
+		respErr.Extensions = ext.Extensions()
+	} else if errors.As(err, &wrapped) && wrapped.Extensions != nil {
+		respErr.Extensions = maps.Clone(wrapped.Extensions)
+	}
+
+	return respErr
+}
+
+// Sets locations of errors of fields by their paths in the query text (errors that have locations aren't changed).
+// Errors are replaced with copies, so errors returned by resolvers aren't changed
+func SetErrorLocations(query string, errs []*ResponseError) {
+	var locations map[string]ErrorLocation
+
+	for i, err := range errs {
+		if len(err.Path) == 0 || len(err.Locations) != 0 {
+			continue
+		}
+
+		// The query is scanned only if there are errors of fields
+		if locations == nil {
+			locations = fieldLocations(query)
+		}
+
+		// List indexes aren't written in the query
+		names := []string{}
+		for _, element := range err.Path {
+			if name, ok := element.(string); ok {
+				names = append(names, name)
+			}
+		}
+
+		if location, ok := locations[strings.Join(names, ".")]; ok {
+			located := *err
+			located.Locations = []ErrorLocation{location}
+			errs[i] = &located
+		}
+	}
+}
+
+// Joins path elements (field names and list indexes) with dots
+func pathString(path []interface{}) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = fmt.Sprint(p)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Returns a new path with the element added to the end (it doesn't change the original path)
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(path)+1), path...), element)
+}
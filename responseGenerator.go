@@ -3,218 +3,1060 @@ package hypeql
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
+	"runtime/debug"
 	"slices"
-	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-// Processes a request's brances recursively
-func (a responseGenerator) recursiveGenerateResponse(r []interface{}, ctx map[string]interface{}, path []string, ds interface{}, deep uint64) (interface{}, error) {
-	// Map for returning
-	var ret map[string]interface{} = map[string]interface{}{}
-	branchRefVal := reflect.ValueOf(ds)
-
-	// Receiving the "Resolve" method
-	if resolveMethod := branchRefVal.MethodByName("Resolve"); resolveMethod.IsValid() {
-		neededFields := []string{} // List of fields tags that needed for this recursive cycle
-
-		// Filling neededFields list
-		for _, i := range r {
-			kind := reflect.TypeOf(i).Kind()
-			if st, ok := i.(string); ok {
-				neededFields = append(neededFields, st)
-			} else if kind == reflect.Slice {
-				q := reflect.ValueOf(i)
-				if q.Len() > 1 {
-					if st, ok := q.Index(0).Interface().(string); ok {
-						neededFields = append(neededFields, st)
-					}
-				}
-			}
-		}
+// State of a single Generate call
+type execution struct {
+	shared  *sharedExecution
+	errors  []*ResponseError // Field errors collected in the partial results mode
+	pending []*pendingField  // Fields whose values are received after the current wave of processing
+}
 
-		// Calling the "Resolve" method that can change context values (you can use context values in another resolver functions)
-		// Use the "Resolve" method to connect with a database for example
-		// func (a ResponseStruct) Resolve(contextMap *map[string]interface{}, neededFields []string) error {...}
-		res := resolveMethod.Call([]reflect.Value{
-			reflect.ValueOf(&ctx),
-			reflect.ValueOf(neededFields),
-		})
+// State of a single Generate call that is shared between all goroutines of the parallel mode
+type sharedExecution struct {
+	semaphore chan struct{}          // Free goroutine slots of the parallel mode (nil if unlimited)
+	tracer    *tracer                // Timings of calls (nil if the tracing mode is off)
+	variables map[string]interface{} // Converted values of variables of the prepared query
+}
 
-		if len(res) == 1 {
-			if err, ok := res[0].Interface().(error); ok && err != nil {
-				return []interface{}{}, err
-			}
-		}
+func (a responseGenerator) newExecution() *execution {
+	shared := &sharedExecution{}
+	if a.Config.MaxGoroutines != 0 {
+		shared.semaphore = make(chan struct{}, a.Config.MaxGoroutines)
 	}
 
-	// List of already processed fields (in the case when one fields mentioned many times in the request body)
-	checked := []string{}
+	if a.Config.Tracing {
+		shared.tracer = newTracer()
+	}
 
-	// Traversing and receiving values of needed fields by listed tags
-a:
-	for _, i := range r {
-		if key, ok := i.(string); ok { // i's value is a basic (single) data (i = field's tag name)
-			// Skipping if field already processed
-			if slices.Contains(checked, key) {
-				continue
-			}
-			checked = append(checked, key)
+	return &execution{
+		shared: shared,
+	}
+}
 
-			newPath := strings.Join(append(path, key), ".")
+// Creates an execution for a goroutine of the parallel mode (errors are collected separately to keep their order deterministic)
+func (a *execution) branch() *execution {
+	return &execution{
+		shared: a.shared,
+	}
+}
+
+// Single field of a request's branch
+type selection struct {
+	key       string
+	list      bool                   // Field is a list of objects
+	fields    []interface{}          // Needed fields of objects from the list
+	arguments map[string]interface{} // Arguments of objects list from body
 
-			// Finding field by tag
-			for i := 0; i < branchRefVal.NumField(); i++ {
-				fieldType := branchRefVal.Type().Field(i)
+	plan     *fieldPlan  // Field of the struct found by the prepared query (nil if the query isn't prepared)
+	children *objectPlan // Plan of objects of the list field (nil if their type is unknown before the execution)
+}
+
+// Field whose resolver function returned a Deferred value
+type pendingField struct {
+	sel      selection
+	deferred Deferred
+	ctx      interface{}   // Pointer to the context
+	path     []interface{} // Path of the field
+	deep     uint64
+	target   *OrderedMap    // Object the field's value is written to
+	filter   *listFilter    // Filter of the list field (nil if the field has no "filter" tag)
+	children *GeneratedType // Generated type of objects of the list field (nil if it's unknown)
+}
 
-				if fieldType.Tag.Get("json") == key && fieldType.Type.Kind() != reflect.Func {
-					// If field found
+// Handles an error of the field located at the path.
+// In the partial results mode the error is collected and nil is returned, so the field becomes null and sibling fields still resolve
+func (a responseGenerator) fieldError(exec *execution, path []interface{}, err error) error {
+	if !a.Config.PartialResults {
+		return err
+	}
 
-					// Getting function middleware name
-					if funcName := fieldType.Tag.Get("fun"); funcName != "" {
-						if q := branchRefVal.MethodByName(funcName); q.IsValid() {
+	exec.errors = append(exec.errors, NewResponseError(err, path))
+	return nil
+}
 
-							// Calling middleware function
-							// Middleware function can replace value of field and use context values (from argument)
-							newVal := q.Call([]reflect.Value{
-								reflect.ValueOf(&ctx),
-							})
+// Returns the error returned by a resolver as the last result value (if any)
+func resolverError(res []reflect.Value) error {
+	if len(res) == 0 {
+		return nil
+	}
 
-							if len(newVal) > 0 && !newVal[0].IsZero() {
-								ret[key] = newVal[0].Interface()
+	last := res[len(res)-1]
+	if last.Type() != reflect.TypeFor[error]() || last.IsNil() {
+		return nil
+	}
 
-								continue a
-							}
-						}
-					}
+	return last.Interface().(error)
+}
 
-					// Use field's value if middleware function is not found
-					ret[key] = branchRefVal.Field(i).Interface()
+// Converts items of a request's branch to the list of selections (fields mentioned many times are listed once)
+func parseSelections(r []interface{}, path []interface{}) ([]selection, error) {
+	selections := []selection{}
 
-					// Finish field finding process (for loop)
-					continue a
-				}
-			}
+	// List of already listed fields (in the case when one fields mentioned many times in the request body)
+	checked := []string{}
 
-			// If field does not found
-			return []interface{}{}, fmt.Errorf(newPath + " not found in the struct")
+	for _, i := range r {
+		var sel selection
 
+		if key, ok := i.(string); ok { // i's value is a basic (single) data (i = field's tag name)
+			sel = selection{
+				key: key,
+			}
 		} else if sliceVal, ok := i.([]interface{}); ok { // i's value is list of objects (branches) (i example: [field's name, object's needed fields, arguments])
 			arguments := map[string]interface{}{}
 
 			if len(sliceVal) != 2 && len(sliceVal) != 3 {
-				return []interface{}{}, fmt.Errorf(strings.Join(path, ".") + " length of list must have two or three elements")
+				return nil, fmt.Errorf(pathString(path) + " length of list must have two or three elements")
 			}
 
 			// Slice has arguments values in third element
 			if len(sliceVal) == 3 {
+				// Copy of arguments, so resolvers can't change the parsed request body (it can be shared by the query cache)
 				if newArguments, ok := sliceVal[2].(map[string]interface{}); ok {
-					arguments = newArguments
+					arguments = maps.Clone(newArguments)
 				}
 			}
 
 			// Getting field's tag name
 			tagName, ok := sliceVal[0].(string)
 			if !ok {
-				return []interface{}{}, fmt.Errorf(strings.Join(path, ".") + " first argument of list must have string type")
+				return nil, fmt.Errorf(pathString(path) + " first argument of list must have string type")
 			}
 
-			// Skipping if field already processed
-			if slices.Contains(checked, tagName) {
-				continue
-			}
-			checked = append(checked, tagName)
-
-			newPath := strings.Join(append(path, tagName), ".")
-
 			// Needed fields of object from field
 			neededFields, ok := sliceVal[1].([]interface{})
 			if !ok {
-				return []interface{}{}, fmt.Errorf(newPath + " second argument of list must have slice type")
+				return nil, fmt.Errorf(pathString(appendPath(path, tagName)) + " second argument of list must have slice type")
 			}
 
-			// Finding field by tag
-			for i := 0; i < branchRefVal.NumField(); i++ {
-				sf := branchRefVal.Type().Field(i)
+			sel = selection{
+				key:       tagName,
+				list:      true,
+				fields:    neededFields,
+				arguments: arguments,
+			}
+		} else {
+			// Unknown data type
+			return nil, fmt.Errorf(pathString(path) + " incorrect data type. The String or Slice types only allowed")
+		}
 
-				if sf.Tag.Get("json") == tagName && sf.Type.Kind() == reflect.Slice {
-					// When field found
+		// Skipping if field already listed
+		if slices.Contains(checked, sel.key) {
+			continue
+		}
+		checked = append(checked, sel.key)
 
-					if a.Config.MaxDeepRecursion != 0 && deep+1 > a.Config.MaxDeepRecursion {
-						return []interface{}{}, fmt.Errorf(newPath + ": max deep recursion reached")
-					}
+		selections = append(selections, sel)
+	}
 
-					l := branchRefVal.Field(i)
+	return selections, nil
+}
 
-					// Getting middleware function's name
-					if funcName := sf.Tag.Get("fun"); funcName != "" {
-						if q := branchRefVal.MethodByName(funcName); q.IsValid() {
+// Returns a copy of the context (pointer to it) for a goroutine of the parallel mode.
+// Maps and slices are copied with nested maps and slices (also in fields of typed contexts), so resolvers can change them.
+// Pointers aren't copied: values they point to (loaders, connections) are shared between goroutines and must be safe for concurrent use
+func cloneContext(ctx interface{}) interface{} {
+	v := reflect.ValueOf(ctx)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ctx
+	}
 
-							// Calling middleware function
-							// Middleware function can replace value of field and use context values (from argument)
-							newVal := q.Call([]reflect.Value{
-								reflect.ValueOf(&ctx),
-								reflect.ValueOf(arguments), // Arguments of objects list from body
-							})
+	clone := reflect.New(v.Type().Elem())
+	clone.Elem().Set(cloneValue(v.Elem(), map[clonedKey]reflect.Value{}))
 
-							if len(newVal) > 0 && !newVal[0].IsZero() && newVal[0].Type().Kind() == reflect.Slice {
-								l = newVal[0]
-							}
-						}
-					}
+	return clone.Interface()
+}
 
-					objects := []interface{}{}
+// Map or slice that is already copied (maps and slices can contain themselves)
+type clonedKey struct {
+	t       reflect.Type
+	pointer uintptr
+	length  int
+}
 
-					// Parsing objects in a new recursion iteration (new branch)
-					for i := 0; i < l.Len(); i++ {
-						p := l.Index(i).Interface()
+// Copies maps and slices of the value recursively (unexported fields of structs are copied shallowly)
+func cloneValue(v reflect.Value, cloned map[clonedKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
 
-						if reflect.TypeOf(p).Kind() != reflect.Struct {
-							continue
-						}
+		key := clonedKey{v.Type(), v.Pointer(), 0}
+		if clone, ok := cloned[key]; ok {
+			return clone
+		}
 
-						i, err := a.recursiveGenerateResponse(neededFields, ctx, append(path, tagName), p, deep+1)
-						if err != nil {
-							return []interface{}{}, err
-						}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		cloned[key] = clone
 
-						objects = append(objects, i)
-					}
+		iter := v.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value(), cloned))
+		}
 
-					// Writing parsed objects
-					ret[tagName] = objects
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
 
-					continue a
-				}
+		key := clonedKey{v.Type(), v.Pointer(), v.Len()}
+		if clone, ok := cloned[key]; ok {
+			return clone
+		}
+
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		cloned[key] = clone
+
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i), cloned))
+		}
+
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem(), cloned))
+
+		return clone
+	case reflect.Struct:
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if field := clone.Field(i); field.CanSet() {
+				field.Set(cloneValue(v.Field(i), cloned))
 			}
+		}
 
-			// Field not found
-			return []interface{}{}, fmt.Errorf(newPath + " field not found in the struct")
+		return clone
+	}
 
-		} else {
-			// Unknown data type
-			return []interface{}{}, fmt.Errorf(strings.Join(path, ".") + " incorrect data type. The String or Slice types only allowed")
+	return v
+}
+
+// Runs the task for each index from 0 to count (path is the path of the object or the list whose tasks are run).
+// In the parallel mode tasks are taken one by one by the current goroutine and by new goroutines while there are free slots.
+// Every new goroutine copies the context once for all its tasks, so resolvers of different goroutines don't race with each other.
+// Returns the first error in the order of indexes
+func (a responseGenerator) runTasks(exec *execution, ctx interface{}, path []interface{}, count int, task func(i int, ctx interface{}, exec *execution) error) error {
+	if !a.Config.Parallel || count < 2 {
+		for i := 0; i < count; i++ {
+			if err := task(i, ctx, exec); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	branches := make([]*execution, count)
+	for i := range branches {
+		branches[i] = exec.branch()
+	}
+	errs := make([]error, count)
+
+	var next atomic.Int64
+	work := func(ctx interface{}) {
+		for {
+			i := int(next.Add(1) - 1)
+			if i >= count {
+				return
+			}
+
+			errs[i] = a.runTask(task, i, ctx, path, branches[i])
+		}
+	}
+
+	// Contexts are copied before the current goroutine starts to change its context
+	var wg sync.WaitGroup
+spawn:
+	for workers := 1; workers < count; workers++ {
+		if exec.shared.semaphore != nil {
+			select {
+			case exec.shared.semaphore <- struct{}{}:
+			default:
+				break spawn
+			}
+		}
+
+		workerCtx := cloneContext(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(workerCtx)
+
+			if exec.shared.semaphore != nil {
+				<-exec.shared.semaphore
+			}
+		}()
+	}
+
+	work(ctx)
+	wg.Wait()
+
+	// Merging errors and pending fields of branches in the order of tasks
+	for _, branch := range branches {
+		exec.errors = append(exec.errors, branch.errors...)
+		exec.pending = append(exec.pending, branch.pending...)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Runs the task of the parallel mode, a panic outside of resolvers (in the authorizer or serialize functions of scalars, for example) is converted to the error
+func (a responseGenerator) runTask(task func(i int, ctx interface{}, exec *execution) error, i int, ctx interface{}, path []interface{}, exec *execution) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		panicErr := &PanicError{
+			Path:  pathString(path),
+			Value: r,
+			Stack: debug.Stack(),
+			debug: a.Config.Debug,
+		}
+
+		if a.Config.PanicHandler != nil {
+			a.Config.PanicHandler(ResolveInfo{Path: path, Context: ctx}, panicErr)
+		}
+
+		err = panicErr
+	}()
+
+	return task(i, ctx, exec)
+}
+
+// Returns tag names of selected fields
+func selectionKeys(selections []selection) []string {
+	keys := make([]string, len(selections))
+	for i, sel := range selections {
+		keys[i] = sel.key
+	}
+
+	return keys
+}
+
+// Calls the "Resolve" method of the branch's struct (if it exists)
+func (a responseGenerator) callResolve(resolveMethod resolverFunc, parent interface{}, neededFields []string, path []interface{}, ctx interface{}, exec *execution) error {
+	if !resolveMethod.valid() {
+		return nil
+	}
+
+	// Calling the "Resolve" method that can change context values (you can use context values in another resolver functions)
+	// Use the "Resolve" method to connect with a database for example
+	// func (a ResponseStruct) Resolve(contextMap *map[string]interface{}, neededFields []string) error {...}
+	_, err := a.withMiddlewares(exec, ResolveInfo{
+		Path:    path,
+		Method:  "Resolve",
+		Parent:  parent,
+		Fields:  neededFields,
+		Context: ctx,
+	}, func(info ResolveInfo) (interface{}, error) {
+		if resolveMethod.call != nil {
+			return resolveMethod.call(info.Context, info.Fields)
+		}
+
+		return resolverResult(resolveMethod.method.Call([]reflect.Value{
+			reflect.ValueOf(info.Context),
+			reflect.ValueOf(info.Fields),
+		}))
+	})
+
+	return err
+}
+
+// Processes a request's brances recursively
+func (a responseGenerator) recursiveGenerateResponse(r []interface{}, ctx interface{}, path []interface{}, ds interface{}, deep uint64, exec *execution) (interface{}, error) {
+	selections, err := parseSelections(r, path)
+	if err != nil {
+		return []interface{}{}, err
+	}
+
+	// Objects of generated types are processed by their executors
+	if object := a.generatedObject(ds); object != nil {
+		return a.generateGenerated(object, selections, selectionKeys(selections), ctx, path, deep, exec)
+	}
+
+	branchRefVal := receiverValue(ds)
+	resolveMethod := methodResolver(branchRefVal.MethodByName("Resolve"))
+
+	return a.generateFields(resolveMethod, branchRefVal.Interface(), selections, selectionKeys(selections), ctx, path, exec, a.reflectedField(branchRefVal, path, deep))
+}
+
+// Processes an object of a request's branch by the prepared plan (the object's type must be the plan's type)
+func (a responseGenerator) generatePlanned(plan *objectPlan, ds interface{}, ctx interface{}, path []interface{}, deep uint64, exec *execution) (interface{}, error) {
+	if object := a.generatedObject(ds); object != nil {
+		return a.generateGenerated(object, plan.selections, plan.keys, ctx, path, deep, exec)
+	}
+
+	branchRefVal := receiverValue(ds)
+	resolveMethod := resolverFunc{}
+	if plan.resolve >= 0 {
+		resolveMethod = methodResolver(branchRefVal.Method(plan.resolve))
+	}
+
+	return a.generateFields(resolveMethod, branchRefVal.Interface(), plan.selections, plan.keys, ctx, path, exec, a.reflectedField(branchRefVal, path, deep))
+}
+
+// Calls the "Resolve" method and receives values of selected fields by the function (by reflection or by the generated executor)
+func (a responseGenerator) generateFields(resolveMethod resolverFunc, parent interface{}, selections []selection, keys []string, ctx interface{}, path []interface{}, exec *execution, field func(sel selection, ctx interface{}, exec *execution) (interface{}, error)) (interface{}, error) {
+	if err := a.callResolve(resolveMethod, parent, keys, path, ctx, exec); err != nil {
+		return []interface{}{}, err
+	}
+
+	// Traversing and receiving values of needed fields by listed tags
+	values := make([]interface{}, len(selections))
+	err := a.runTasks(exec, ctx, path, len(selections), func(i int, ctx interface{}, exec *execution) error {
+		var err error
+		values[i], err = field(selections[i], ctx, exec)
+
+		return err
+	})
+	if err != nil {
+		return []interface{}{}, err
+	}
+
+	// Object for returning (fields are in the same order as in the request)
+	ret := NewOrderedMap()
+	for i, sel := range selections {
+		// Value of a pending field is written later
+		if pending, ok := values[i].(*pendingField); ok {
+			pending.target = ret
+			exec.pending = append(exec.pending, pending)
+			values[i] = nil
 		}
+
+		ret.Set(sel.key, values[i])
 	}
 
 	return ret, nil
 }
 
-// Processes a request body and returns a result (the first is JSON string)
-func (a responseGenerator) Generate(requestBody []interface{}, dataStruct interface{}, initContext map[string]interface{}) (string, error) {
+// Returns the function that receives values of selected fields of the object by reflection
+func (a responseGenerator) reflectedField(branchRefVal reflect.Value, path []interface{}, deep uint64) func(sel selection, ctx interface{}, exec *execution) (interface{}, error) {
+	return func(sel selection, ctx interface{}, exec *execution) (interface{}, error) {
+		if sel.list {
+			return a.generateList(sel, ctx, path, branchRefVal, deep, exec)
+		}
+
+		return a.generateValue(sel, ctx, path, branchRefVal, exec)
+	}
+}
+
+// Receives a value of basic (single) field
+func (a responseGenerator) generateValue(sel selection, ctx interface{}, path []interface{}, branchRefVal reflect.Value, exec *execution) (interface{}, error) {
+	fieldPath := appendPath(path, sel.key)
+
+	// Finding field by tag
+	field, ok := a.findField(sel, branchRefVal, isValueField)
+	if !ok {
+		// If field does not found
+		return nil, a.fieldError(exec, fieldPath, fmt.Errorf(pathString(fieldPath)+" not found in the struct"))
+	}
+
+	return a.resolveValue(sel, field, ctx, fieldPath, exec)
+}
+
+// Struct field of the selection found by reflection or passed by the generated code
+type objectField struct {
+	sf       reflect.StructField
+	value    interface{}
+	funcName string         // Name of the resolver function from the "fun" tag
+	resolver resolverFunc   // Invalid if the struct doesn't have the resolver function
+	parent   interface{}    // Object of the field (the receiver of the resolver function)
+	children *GeneratedType // Generated type of objects of the list field (nil if it's unknown)
+}
+
+// Receives the value of the found basic (single) field by its resolver function
+func (a responseGenerator) resolveValue(sel selection, field objectField, ctx interface{}, fieldPath []interface{}, exec *execution) (interface{}, error) {
+	// Resolver function is not called if the request doesn't have permission to receive the field
+	if err := a.authorize(field.sf, ctx, fieldPath, field.parent); err != nil {
+		return nil, a.fieldError(exec, fieldPath, err)
+	}
+
+	// Getting function middleware
+	if q := field.resolver; q.valid() {
+		// Calling middleware function (through middlewares of the config)
+		// Middleware function can replace value of field and use context values (from argument)
+		newVal, err := a.withMiddlewares(exec, ResolveInfo{
+			Path:    fieldPath,
+			Field:   sel.key,
+			Method:  field.funcName,
+			Parent:  field.parent,
+			Context: ctx,
+		}, func(info ResolveInfo) (interface{}, error) {
+			return a.callResolver(q, info)
+		})
+
+		// Middleware function can also return an error as the second value
+		if err != nil {
+			return nil, a.fieldError(exec, fieldPath, err)
+		}
+
+		if !emptyResult(newVal, q.t) {
+			if deferred, ok := newVal.(Deferred); ok {
+				return &pendingField{
+					sel:      sel,
+					deferred: deferred,
+					ctx:      ctx,
+					path:     fieldPath,
+				}, nil
+			}
+
+			return a.serialize(exec, fieldPath, newVal)
+		}
+	}
+
+	// Use field's value if middleware function is not found
+	return a.serialize(exec, fieldPath, field.value)
+}
+
+// Checks that the struct field can be a basic (single) field
+func isValueField(sf reflect.StructField, scalars *ScalarRegistry) bool {
+	return sf.Type.Kind() != reflect.Func
+}
+
+// Checks that the struct field can be a list field (slice of objects or object)
+func isListField(sf reflect.StructField, scalars *ScalarRegistry) bool {
+	return sf.Type.Kind() == reflect.Slice || isObjectType(sf.Type, scalars)
+}
+
+// Finds the struct field of the selection (by the prepared plan or the tag) with its value and resolver function
+func (a responseGenerator) findField(sel selection, branchRefVal reflect.Value, matches func(sf reflect.StructField, scalars *ScalarRegistry) bool) (objectField, bool) {
+	structVal := reflect.Indirect(branchRefVal)
+
+	if sel.plan != nil {
+		field := objectField{
+			sf:       sel.plan.field,
+			value:    structVal.Field(sel.plan.field.Index[0]).Interface(),
+			funcName: sel.plan.funcName,
+			parent:   branchRefVal.Interface(),
+		}
+
+		if sel.plan.method >= 0 {
+			field.resolver = methodResolver(branchRefVal.Method(sel.plan.method))
+		}
+
+		return field, true
+	}
+
+	for i := 0; i < structVal.NumField(); i++ {
+		sf := structVal.Type().Field(i)
+		if sf.Tag.Get("json") != sel.key || !matches(sf, a.Config.Scalars) {
+			continue
+		}
+
+		field := objectField{
+			sf:       sf,
+			value:    structVal.Field(i).Interface(),
+			funcName: sf.Tag.Get("fun"),
+			parent:   branchRefVal.Interface(),
+		}
+
+		if field.funcName != "" {
+			field.resolver = methodResolver(branchRefVal.MethodByName(field.funcName))
+		}
+
+		return field, true
+	}
+
+	return objectField{}, false
+}
+
+// Converts the value of a field with the serialize function of its custom scalar type
+func (a responseGenerator) serialize(exec *execution, fieldPath []interface{}, value interface{}) (interface{}, error) {
+	value, err := a.Config.Scalars.serialize(value, fieldPath)
+	if err != nil {
+		return nil, a.fieldError(exec, fieldPath, err)
+	}
+
+	return value, nil
+}
+
+// Receives objects of list field and processes them in a new recursion iteration
+func (a responseGenerator) generateList(sel selection, ctx interface{}, path []interface{}, branchRefVal reflect.Value, deep uint64, exec *execution) (interface{}, error) {
+	l, pending, err := a.findList(sel, ctx, path, branchRefVal, deep, exec)
+	if pending != nil {
+		return pending, nil
+	}
+
+	if err != nil || l == nil {
+		return nil, err
+	}
+
+	return a.generateObjects(sel, ctx, appendPath(path, sel.key), l, nil, deep, exec)
+}
+
+// Calls the resolver function with the context and the arguments if the function receives them (map or arguments struct)
+func (a responseGenerator) callResolver(q resolverFunc, info ResolveInfo) (interface{}, error) {
+	var args reflect.Value
+
+	if q.t.NumIn() == 2 {
+		arguments := info.Arguments
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+
+		args = reflect.ValueOf(arguments)
+
+		// Resolver function can receive the arguments struct instead of the map
+		if isArgumentsType(q.t.In(1)) {
+			var err error
+			if args, err = bindArguments(arguments, q.t.In(1), info.Path, a.Config.Scalars); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Generated code calls the method directly
+	if q.call != nil {
+		var arguments interface{}
+		if args.IsValid() {
+			arguments = args.Interface()
+		}
+
+		return q.call(info.Context, arguments)
+	}
+
+	in := []reflect.Value{reflect.ValueOf(info.Context)}
+	if args.IsValid() {
+		in = append(in, args)
+	}
+
+	return resolverResult(q.method.Call(in))
+}
+
+// Receives the slice of objects of list field (or the object of object field).
+// Returns nil if the field is null (in the partial results mode) or its value is pending
+func (a responseGenerator) findList(sel selection, ctx interface{}, path []interface{}, branchRefVal reflect.Value, deep uint64, exec *execution) (interface{}, *pendingField, error) {
+	fieldPath := appendPath(path, sel.key)
+
+	// Finding field by tag
+	field, ok := a.findField(sel, branchRefVal, isListField)
+	if !ok {
+		// Field not found
+		return nil, nil, a.fieldError(exec, fieldPath, fmt.Errorf(pathString(fieldPath)+" field not found in the struct"))
+	}
+
+	return a.resolveList(sel, field, ctx, fieldPath, deep, exec)
+}
+
+// Receives the slice of objects (or the object) of the found list field by its resolver function, filters and sorts the slice
+func (a responseGenerator) resolveList(sel selection, field objectField, ctx interface{}, fieldPath []interface{}, deep uint64, exec *execution) (interface{}, *pendingField, error) {
+	if a.Config.MaxDeepRecursion != 0 && deep+1 > a.Config.MaxDeepRecursion {
+		return nil, nil, a.fieldError(exec, fieldPath, fmt.Errorf(pathString(fieldPath)+": max deep recursion reached"))
+	}
+
+	// Resolver function is not called if the request doesn't have permission to receive the field
+	if err := a.authorize(field.sf, ctx, fieldPath, field.parent); err != nil {
+		return nil, nil, a.fieldError(exec, fieldPath, err)
+	}
+
+	// The "where" and "orderBy" arguments of lists with the "filter" tag aren't passed to the resolver function
+	filter, arguments, err := a.listArguments(sel, field.sf, fieldPath, exec)
+	if err != nil {
+		return nil, nil, a.fieldError(exec, fieldPath, err)
+	}
+
+	list := field.value
+
+	// Getting middleware function
+	if q := field.resolver; q.valid() {
+		// Calling middleware function (through middlewares of the config)
+		// Middleware function can replace value of field and use context values (from argument)
+		newVal, err := a.withMiddlewares(exec, ResolveInfo{
+			Path:      fieldPath,
+			Field:     sel.key,
+			Method:    field.funcName,
+			Parent:    field.parent,
+			Arguments: arguments, // Arguments of objects list from body
+			Context:   ctx,
+		}, func(info ResolveInfo) (interface{}, error) {
+			return a.callResolver(q, info)
+		})
+
+		// Middleware function can also return an error as the second value
+		if err != nil {
+			return nil, nil, a.fieldError(exec, fieldPath, err)
+		}
+
+		if !emptyResult(newVal, q.t) {
+			if deferred, ok := newVal.(Deferred); ok {
+				return nil, &pendingField{
+					sel:      sel,
+					deferred: deferred,
+					ctx:      ctx,
+					path:     fieldPath,
+					deep:     deep,
+					filter:   filter,
+					children: field.children,
+				}, nil
+			}
+
+			// Unwrapping values returned as interfaces
+			if field.children.owns(newVal) || reflect.ValueOf(newVal).Kind() == reflect.Slice || isObject(newVal) {
+				list = newVal
+			}
+		}
+	}
+
+	// Filtering and sorting are applied after the resolver function
+	if filter != nil {
+		if l := reflect.ValueOf(list); l.Kind() == reflect.Slice {
+			list = filter.apply(l).Interface()
+		}
+	}
+
+	return list, nil, nil
+}
+
+// Returns the filter of the list field and arguments of its resolver function.
+// Filters of prepared queries are parsed once if their arguments don't have variables
+func (a responseGenerator) listArguments(sel selection, sf reflect.StructField, fieldPath []interface{}, exec *execution) (*listFilter, map[string]interface{}, error) {
+	if sel.plan == nil {
+		return parseListFilter(sf, sel.arguments, fieldPath)
+	}
+
+	if !sel.plan.variables {
+		return sel.plan.filter, maps.Clone(sel.plan.arguments), nil
+	}
+
+	return parseListFilter(sf, substituteVariables(sel.arguments, exec.shared.variables), fieldPath)
+}
+
+// Returns objects of the list field's value: elements of the slice (nil pointers are returned as nil objects) or the object itself (false if the value isn't a slice).
+// Objects of the generated type of children are bound to its executor, so the slice isn't read by reflection
+func listObjects(list interface{}, children *GeneratedType) ([]interface{}, bool) {
+	if objects, ok := children.bindAll(list); ok {
+		return objects, true
+	}
+
+	if l := reflect.ValueOf(list); l.Kind() == reflect.Slice {
+		return listElements(l), true
+	}
+
+	return nil, false
+}
+
+// Returns the object of object field bound to the generated type of children (nil if the value isn't an object, nil pointers are written as null)
+func fieldObject(value interface{}, children *GeneratedType) interface{} {
+	if object := children.bind(value); object != nil {
+		return object
+	}
+
+	if !isObject(value) {
+		return nil
+	}
+
+	return value
+}
+
+// Processes objects of the list (or the object of object field) in a new recursion iteration
+func (a responseGenerator) generateObjects(sel selection, ctx interface{}, fieldPath []interface{}, list interface{}, children *GeneratedType, deep uint64, exec *execution) (interface{}, error) {
+	elements, ok := listObjects(list, children)
+	if !ok {
+		// Nil pointers are written as null
+		ds := fieldObject(list, children)
+		if ds == nil {
+			return nil, nil
+		}
+
+		object, err := a.generateObject(sel, ctx, fieldPath, ds, deep+1, exec)
+		if err != nil {
+			// The whole object becomes null if its "Resolve" method fails
+			return nil, a.fieldError(exec, fieldPath, err)
+		}
+
+		return object, nil
+	}
+
+	objects := make([]interface{}, len(elements))
+
+	// Parsing objects in a new recursion iteration (new branch)
+	err := a.runTasks(exec, ctx, fieldPath, len(elements), func(i int, ctx interface{}, exec *execution) error {
+		elementPath := appendPath(fieldPath, i)
+
+		// Nil pointers are written as null
+		if elements[i] == nil {
+			objects[i] = nil
+			return nil
+		}
+
+		object, err := a.generateObject(sel, ctx, elementPath, elements[i], deep+1, exec)
+		if err != nil {
+			// The whole object becomes null if its "Resolve" method fails
+			if err := a.fieldError(exec, elementPath, err); err != nil {
+				return err
+			}
+
+			object = nil
+		}
+
+		objects[i] = object
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Writing parsed objects
+	return objects, nil
+}
+
+// Processes the object of list field by the prepared plan (if the object has the type of the plan) or by needed fields of the selection
+func (a responseGenerator) generateObject(sel selection, ctx interface{}, path []interface{}, ds interface{}, deep uint64, exec *execution) (interface{}, error) {
+	if sel.children != nil {
+		if object := a.generatedObject(ds); object != nil {
+			if object.receiverType == sel.children.receiverType {
+				return a.generateGenerated(object, sel.children.selections, sel.children.keys, ctx, path, deep, exec)
+			}
+		} else if branchRefVal := receiverValue(ds); branchRefVal.Type() == sel.children.receiverType {
+			return a.generatePlanned(sel.children, branchRefVal.Interface(), ctx, path, deep, exec)
+		}
+	}
+
+	// Variables of prepared queries are applied to fields of objects processed without the plan
+	fields := sel.fields
+	if exec.shared.variables != nil {
+		var err error
+		if fields, err = ApplyVariables(fields, exec.shared.variables); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.recursiveGenerateResponse(fields, ctx, path, ds, deep, exec)
+}
+
+// Returns objects (structs or pointers to structs) of the list, nil pointers are returned as nil objects
+func listElements(l reflect.Value) []interface{} {
+	elements := []interface{}{}
+	for i := 0; i < l.Len(); i++ {
+		p := l.Index(i).Interface()
+
+		if p == nil || reflect.ValueOf(p).Kind() == reflect.Pointer && reflect.ValueOf(p).IsNil() {
+			elements = append(elements, nil)
+			continue
+		}
+
+		if !isObject(p) {
+			continue
+		}
+
+		elements = append(elements, p)
+	}
+
+	return elements
+}
+
+// Checks that fields of the type (struct or pointer to struct) can be requested.
+// Structs of custom scalar types and types that marshal themselves are written as values
+func isObjectType(t reflect.Type, scalars *ScalarRegistry) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && scalars.name(t) == "Any"
+}
+
+// Checks that the value is a struct or a non-nil pointer to a struct
+func isObject(ds interface{}) bool {
+	v := reflect.ValueOf(ds)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+
+		v = v.Elem()
+	}
+
+	return v.Kind() == reflect.Struct
+}
+
+// Returns the value whose methods (resolvers) are called.
+// Structs that have methods with pointer receivers are copied to a new pointer, so methods with both value and pointer receivers are available
+func receiverValue(ds interface{}) reflect.Value {
+	v := reflect.ValueOf(ds)
+	if v.Kind() == reflect.Struct && reflect.PointerTo(v.Type()).NumMethod() > v.NumMethod() {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+
+		return ptr
+	}
+
+	return v
+}
+
+// Receives the deferred value of pending field (for list fields it's the slice of objects)
+func (a responseGenerator) receive(p *pendingField) (interface{}, interface{}, error) {
+	value, err := a.recovered(func(info ResolveInfo) (interface{}, error) {
+		return p.deferred.Value()
+	})(ResolveInfo{
+		Path:    p.path,
+		Field:   p.sel.key,
+		Method:  "Value",
+		Context: p.ctx,
+	})
+	if err != nil || value == nil {
+		return value, nil, err
+	}
+
+	if !p.sel.list {
+		value, err = a.Config.Scalars.serialize(value, p.path)
+		return value, nil, err
+	}
+
+	l := reflect.ValueOf(value)
+	if l.Kind() != reflect.Slice && !isObject(value) {
+		return nil, nil, fmt.Errorf(pathString(p.path) + " deferred value of list field must have slice type (or struct type for object field)")
+	}
+
+	if l.Kind() == reflect.Slice {
+		return nil, p.filter.apply(l).Interface(), nil
+	}
+
+	return nil, value, nil
+}
+
+// Receives values of pending fields wave by wave.
+// All deferred values of a wave are requested before the first of them is received, so loaders load them with one batch
+func (a responseGenerator) resolvePending(exec *execution) error {
+	for len(exec.pending) > 0 {
+		wave := exec.pending
+		exec.pending = nil // Pending fields of nested objects are moved to the next wave
+
+		for _, p := range wave {
+			value, l, err := a.receive(p)
+			if err == nil && l != nil {
+				value, err = a.generateObjects(p.sel, p.ctx, p.path, l, p.children, p.deep, exec)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err != nil {
+				if err := a.fieldError(exec, p.path, err); err != nil {
+					return err
+				}
+
+				value = nil
+			}
+
+			p.target.Set(p.sel.key, value)
+		}
+	}
+
+	return nil
+}
+
+// Result of a processed request
+type Result struct {
+	Data       *OrderedMap            `json:"data"`                 // Null if the root "Resolve" method failed in the partial results mode
+	Errors     []*ResponseError       `json:"errors,omitempty"`     // Errors of fields in the partial results mode
+	Extensions map[string]interface{} `json:"extensions,omitempty"` // Has the "tracing" object in the tracing mode
+}
+
+// Returns timings of the request (nil if the tracing mode is off)
+func (a *Result) Trace() *Trace {
+	trace, _ := a.Extensions["tracing"].(*Trace)
+	return trace
+}
+
+// Returns the "extensions" object of the response (nil if there is nothing to write)
+func (a responseGenerator) extensions(exec *execution) map[string]interface{} {
+	if exec.shared.tracer == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"tracing": exec.shared.tracer.finish(),
+	}
+}
+
+// Checks if the response is written as {"data": ..., "errors": [...], "extensions": {...}} instead of the data only
+func (a responseGenerator) enveloped() bool {
+	return a.Config.PartialResults || a.Config.Tracing
+}
+
+// Processes a request body and returns a result as objects with the requested order of fields.
+// Use "Data.Map()" of the result if you need a map
+func (a responseGenerator) GenerateResult(requestBody []interface{}, dataStruct interface{}, initContext map[string]interface{}) (*Result, error) {
+	return a.generateResult(requestBody, dataStruct, &initContext)
+}
+
+// Processes a request body with the context (pointer to the context map or to the typed context) that resolvers receive
+func (a responseGenerator) generateResult(requestBody []interface{}, dataStruct interface{}, ctx interface{}) (*Result, error) {
 	// dataStruct argument must be Struct
-	if reflect.TypeOf(dataStruct).Kind() != reflect.Struct {
-		return "", fmt.Errorf("dataStruct argument must be instance of struct")
+	if !isObject(dataStruct) {
+		return nil, fmt.Errorf("dataStruct argument must be instance of struct or pointer to struct")
+	}
+
+	return a.run(a.newExecution(), func(exec *execution) (interface{}, error) {
+		// Start recursion to process all fields in the request
+		return a.recursiveGenerateResponse(requestBody, ctx, []interface{}{}, dataStruct, 1, exec)
+	})
+}
+
+// Processes the root object and pending fields
+func (a responseGenerator) run(exec *execution, root func(exec *execution) (interface{}, error)) (*Result, error) {
+	result := &Result{}
+
+	i, err := root(exec)
+	if err == nil {
+		result.Data = i.(*OrderedMap)
+		err = a.resolvePending(exec)
+	}
+	if err != nil {
+		// The root "Resolve" method failed, so there is no data at all
+		if err := a.fieldError(exec, []interface{}{}, err); err != nil {
+			return nil, err
+		}
+		result.Data = nil
 	}
 
-	// Start recursion to process all fields in the request
-	i, err := a.recursiveGenerateResponse(requestBody, initContext, []string{}, dataStruct, 1)
+	result.Errors = exec.errors
+	result.Extensions = a.extensions(exec)
+
+	return result, nil
+}
+
+// Processes a request body and returns a result (the first is JSON string)
+func (a responseGenerator) Generate(requestBody []interface{}, dataStruct interface{}, initContext map[string]interface{}) (string, error) {
+	return a.generate(requestBody, dataStruct, &initContext)
+}
+
+func (a responseGenerator) generate(requestBody []interface{}, dataStruct interface{}, ctx interface{}) (string, error) {
+	result, err := a.generateResult(requestBody, dataStruct, ctx)
 	if err != nil {
 		return "", err
 	}
 
+	var out interface{} = result.Data
+	if a.enveloped() {
+		out = result
+	}
+
 	// Converting result to JSON string and return
-	q, err := json.Marshal(i)
+	q, err := json.Marshal(out)
 	if err != nil {
 		return "", fmt.Errorf("JSON converting error")
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"testing"
+	"time"
 )
 
 // TEST #1
@@ -82,7 +83,7 @@ func TestGeneral(t *testing.T) {
 		t.Fatal("Process error: " + err.Error())
 	}
 
-	mustBe := `{"bar":true,"clist":[{"text":"Hello"},{"text":"Hello"}],"foo":""}`
+	mustBe := `{"foo":"","bar":true,"clist":[{"text":"Hello"},{"text":"Hello"}]}`
 
 	if resp != mustBe {
 		t.Fatal("Not equal")
@@ -160,3 +161,274 @@ func TestDeepRecursionLimitGenerator(t *testing.T) {
 		t.Fatal("Not equal")
 	}
 }
+
+// TEST #4
+
+type Partial struct {
+	Name   string `json:"name"`
+	Broken string `json:"broken" fun:"Rbroken"`
+	Items  []Item `json:"items"`
+}
+
+type Item struct {
+	Id int `json:"id"`
+}
+
+func (a Partial) Rbroken(ctx *map[string]any) (string, error) {
+	return "", fmt.Errorf("broken field")
+}
+
+func (a Item) Resolve(ctx *map[string]any, fields []string) error {
+	if a.Id == 2 {
+		return fmt.Errorf("item is unavailable")
+	}
+
+	return nil
+}
+
+func TestPartialResults(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		PartialResults: true,
+	})
+
+	parsed, err := parser.Parse("{name,broken,items{id}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(parsed, Partial{
+		Name:  "partial",
+		Items: []Item{{Id: 1}, {Id: 2}},
+	}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	mustBe := `{"data":{"name":"partial","broken":null,"items":[{"id":1},null]},"errors":[{"message":"broken field","path":["broken"]},{"message":"item is unavailable","path":["items",1]}]}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	// Without the partial results mode the first error fails the whole response
+	_, err = NewResponseGenerator(ResponseGeneratorConfig{}).Generate(parsed, Partial{}, map[string]any{})
+	if err == nil || err.Error() != "broken field" {
+		t.Fatal("Not equal")
+	}
+}
+
+// TEST #5
+
+type Catalog struct {
+	Products []Product `json:"products" fun:"Rproducts"`
+}
+
+type Product struct {
+	Id    int    `json:"id"`
+	Title string `json:"title" fun:"Rtitle"`
+	Price int    `json:"price" fun:"Rprice"`
+}
+
+func (a Catalog) Rproducts(ctx *map[string]any, args map[string]any) []Product {
+	products := []Product{}
+	for i := 0; i < 50; i++ {
+		products = append(products, Product{Id: i})
+	}
+
+	return products
+}
+
+func (a Product) Resolve(ctx *map[string]any, fields []string) {
+	(*ctx)["title"] = fmt.Sprint("Product #", a.Id)
+}
+
+func (a Product) Rtitle(ctx *map[string]any) any {
+	time.Sleep(time.Millisecond)
+	return (*ctx)["title"]
+}
+
+func (a Product) Rprice(ctx *map[string]any) int {
+	time.Sleep(time.Millisecond)
+	return a.Id * 10
+}
+
+func TestParallel(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	sequential := NewResponseGenerator(ResponseGeneratorConfig{})
+	parallel := NewResponseGenerator(ResponseGeneratorConfig{
+		Parallel:      true,
+		MaxGoroutines: 8,
+	})
+
+	parsed, err := parser.Parse("{products{id,title,price}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	mustBe, err := sequential.Generate(parsed, Catalog{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	for i := 0; i < 5; i++ {
+		resp, err := parallel.Generate(parsed, Catalog{}, map[string]any{})
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		if resp != mustBe {
+			t.Fatal("Not equal")
+		}
+	}
+}
+
+type Warehouse struct {
+	Crates []Crate `json:"crates" fun:"Rcrates"`
+}
+
+type Crate struct {
+	Id    int    `json:"id"`
+	Label string `json:"label" fun:"Rlabel"`
+}
+
+func (a Warehouse) Rcrates(ctx *map[string]any) []Crate {
+	crates := []Crate{}
+	for i := 0; i < 20; i++ {
+		crates = append(crates, Crate{Id: i})
+	}
+
+	return crates
+}
+
+// Writes to maps and slices nested in the context
+func (a Crate) Resolve(ctx *map[string]any, fields []string) {
+	(*ctx)["visited"].(map[string]any)[fmt.Sprint(a.Id)] = true
+	(*ctx)["path"] = append((*ctx)["path"].([]any), a.Id)
+}
+
+func (a Crate) Rlabel(ctx *map[string]any) string {
+	path := (*ctx)["path"].([]any)
+	return fmt.Sprint((*ctx)["visited"].(map[string]any)[fmt.Sprint(a.Id)], " ", path[len(path)-1])
+}
+
+// Run with "go test -race": resolvers of list objects write to the nested context map in goroutines
+func TestParallelContext(t *testing.T) {
+	parsed, err := NewQueryParser(QueryParserConfig{}).Parse("{crates{id,label}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	for _, maxGoroutines := range []uint64{0, 3} {
+		// The context contains itself
+		ctx := map[string]any{"visited": map[string]any{}, "path": []any{"root"}}
+		ctx["self"] = ctx
+
+		generator := NewResponseGenerator(ResponseGeneratorConfig{Parallel: true, MaxGoroutines: maxGoroutines})
+		result, err := generator.GenerateResult(parsed, Warehouse{}, ctx)
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		crates, _ := result.Data.Get("crates")
+		for i, crate := range crates.([]any) {
+			if label, _ := crate.(*OrderedMap).Get("label"); label != fmt.Sprint("true ", i) {
+				t.Fatal("Not equal: ", label)
+			}
+		}
+	}
+}
+
+func TestCloneContext(t *testing.T) {
+	type typedContext struct {
+		User   string
+		Roles  []string
+		Counts map[string]int
+		Loader *Loader[int, int]
+	}
+
+	ctx := &typedContext{User: "John", Roles: []string{"admin"}, Counts: map[string]int{"a": 1}, Loader: NewLoader(func(keys []int) ([]int, error) { return keys, nil })}
+	clone := cloneContext(ctx).(*typedContext)
+
+	clone.Roles[0] = "guest"
+	clone.Counts["a"] = 2
+
+	if ctx.Roles[0] != "admin" || ctx.Counts["a"] != 1 || clone.User != "John" || clone.Loader != ctx.Loader {
+		t.Fatal("Not equal")
+	}
+
+	// Maps and slices that contain themselves are copied once
+	nested := map[string]any{}
+	nested["nested"] = nested
+	list := []any{nil}
+	list[0] = list
+	mapClone := *cloneContext(&map[string]any{"nested": nested, "list": list}).(*map[string]any)
+
+	clonedNested := mapClone["nested"].(map[string]any)
+	clonedNested["changed"] = true
+	if nested["changed"] != nil || clonedNested["nested"].(map[string]any)["changed"] != true || len(mapClone["list"].([]any)[0].([]any)) != 1 {
+		t.Fatal("Not equal")
+	}
+}
+
+// TEST #6
+
+type Shelf struct {
+	Label string `json:"label" fun:"Rlabel"`
+	Boxes []*Box `json:"boxes" fun:"Rboxes"`
+	Items []any  `json:"items"`
+}
+
+type Box struct {
+	Size int    `json:"size"`
+	Name string `json:"name" fun:"Rname"`
+}
+
+func (a *Shelf) Rlabel(ctx *map[string]any) string {
+	return "Shelf: " + a.Label
+}
+
+func (a *Shelf) Rboxes(ctx *map[string]any, args map[string]any) []*Box {
+	return []*Box{{Size: 1}, nil, {Size: 3}}
+}
+
+func (a *Box) Resolve(ctx *map[string]any, fields []string) {
+	(*ctx)["size"] = a.Size
+}
+
+func (a *Box) Rname(ctx *map[string]any) string {
+	return fmt.Sprint("Box #", (*ctx)["size"])
+}
+
+func TestPointers(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	parsed, err := parser.Parse("{label,boxes{size,name},items{size}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	mustBe := `{"label":"Shelf: top","boxes":[{"size":1,"name":"Box #1"},null,{"size":3,"name":"Box #3"}],"items":[{"size":4},null,{"size":5}]}`
+
+	shelf := Shelf{
+		Label: "top",
+		Items: []any{Box{Size: 4}, nil, &Box{Size: 5}},
+	}
+
+	// Struct value and pointer to struct give the same result
+	for _, dataStruct := range []any{shelf, &shelf} {
+		resp, err := generator.Generate(parsed, dataStruct, map[string]any{})
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		if resp != mustBe {
+			t.Fatal("Not equal: " + resp)
+		}
+	}
+
+	if _, err := generator.Generate(parsed, (*Shelf)(nil), map[string]any{}); err == nil {
+		t.Fatal("No errors")
+	}
+}
@@ -0,0 +1,379 @@
+package hypeql
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Writer of the streaming mode that writes JSON to the buffer and flushes it after the configured number of bytes
+type streamWriter struct {
+	w         io.Writer
+	buf       *bufio.Writer
+	flushSize uint64 // Stay 0 to flush only at the end
+	unflushed uint64
+	written   uint64 // Number of all written bytes
+	err       error  // First write error, next writes are skipped
+
+	// Parts of the result after the first pending field, they are written when values of pending fields are received
+	chunks []streamChunk
+}
+
+// Written text or the pending field whose value is written later
+type streamChunk struct {
+	text    []byte
+	pending *pendingField
+}
+
+func newStreamWriter(w io.Writer, flushSize uint64) *streamWriter {
+	return &streamWriter{
+		w:         w,
+		buf:       bufio.NewWriter(w),
+		flushSize: flushSize,
+	}
+}
+
+func (a *streamWriter) write(s string) {
+	if a.err != nil {
+		return
+	}
+
+	if len(a.chunks) != 0 {
+		last := &a.chunks[len(a.chunks)-1]
+		if last.pending != nil {
+			a.chunks = append(a.chunks, streamChunk{})
+			last = &a.chunks[len(a.chunks)-1]
+		}
+
+		last.text = append(last.text, s...)
+		a.written += uint64(len(s))
+		return
+	}
+
+	n, err := a.buf.WriteString(s)
+	a.err = err
+	a.unflushed += uint64(n)
+	a.written += uint64(n)
+
+	if a.flushSize != 0 && a.unflushed >= a.flushSize {
+		a.flush()
+	}
+}
+
+// Writes a value converted to JSON
+func (a *streamWriter) value(v interface{}) error {
+	q, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("JSON converting error")
+	}
+
+	a.write(string(q))
+	return nil
+}
+
+// Leaves the place for the value of the pending field, next parts of the result are kept until the value is received.
+// The value is written to the object of the pending field, so "resolvePending" receives it like values of "Generate"
+func (a *streamWriter) pending(p *pendingField, exec *execution) {
+	p.target = NewOrderedMap()
+	exec.pending = append(exec.pending, p)
+
+	a.chunks = append(a.chunks, streamChunk{pending: p})
+	a.written++ // The field isn't empty, so its object can't be replaced by null
+}
+
+// Writes kept parts of the result with received values of pending fields
+func (a *streamWriter) release() error {
+	chunks := a.chunks
+	a.chunks = nil
+
+	for i := range chunks {
+		if chunks[i].pending == nil {
+			a.write(string(chunks[i].text))
+			continue
+		}
+
+		value, _ := chunks[i].pending.target.Get(chunks[i].pending.sel.key)
+		if err := a.value(value); err != nil {
+			return err
+		}
+	}
+
+	return a.err
+}
+
+// Writes the buffer to the writer and flushes the writer if it's able to (http.ResponseWriter, for example)
+func (a *streamWriter) flush() {
+	if a.err != nil {
+		return
+	}
+
+	a.err = a.buf.Flush()
+	a.unflushed = 0
+	if a.err != nil {
+		return
+	}
+
+	switch f := a.w.(type) {
+	case interface{ Flush() }:
+		f.Flush()
+	case interface{ Flush() error }:
+		a.err = f.Flush()
+	}
+}
+
+// Processes a request's branch like recursiveGenerateResponse but writes the object to the stream while processing it.
+// Nothing is written when an error is returned before the object is started (so the object can be replaced by null)
+func (a responseGenerator) streamObject(sw *streamWriter, r []interface{}, ctx interface{}, path []interface{}, ds interface{}, deep uint64, exec *execution) error {
+	selections, err := parseSelections(r, path)
+	if err != nil {
+		return err
+	}
+
+	// Objects of generated types are processed by their executors
+	object := a.generatedObject(ds)
+
+	var branchRefVal reflect.Value
+	if object != nil {
+		err = a.callResolve(object.resolve, object.parent, selectionKeys(selections), path, ctx, exec)
+	} else {
+		branchRefVal = receiverValue(ds)
+		err = a.callResolve(methodResolver(branchRefVal.MethodByName("Resolve")), branchRefVal.Interface(), selectionKeys(selections), path, ctx, exec)
+	}
+	if err != nil {
+		return err
+	}
+
+	generator := &a
+
+	sw.write("{")
+
+	for i, sel := range selections {
+		if sw.err != nil {
+			return sw.err
+		}
+
+		if i != 0 {
+			sw.write(",")
+		}
+
+		if err := sw.value(sel.key); err != nil {
+			return err
+		}
+		sw.write(":")
+
+		// Generated executors write list fields to the stream
+		var value interface{}
+		if object != nil {
+			value, err = object.generated.execute(&GeneratedSelection{
+				generator: generator,
+				object:    object,
+				sel:       sel,
+				ctx:       ctx,
+				path:      path,
+				deep:      deep,
+				exec:      exec,
+				stream:    sw,
+			}, object.receiver)
+		} else if sel.list {
+			err = a.streamFieldList(sw, sel, ctx, path, branchRefVal, deep, exec)
+		} else {
+			value, err = a.generateValue(sel, ctx, path, branchRefVal, exec)
+		}
+		if err != nil {
+			return err
+		}
+
+		if sel.list {
+			continue
+		}
+
+		// Deferred values are received after all objects, so loaders load them in batches
+		if pending, ok := value.(*pendingField); ok {
+			sw.pending(pending, exec)
+			continue
+		}
+
+		if err := sw.value(value); err != nil {
+			return err
+		}
+	}
+
+	sw.write("}")
+
+	return sw.err
+}
+
+// Finds the list field by reflection and writes its objects to the stream
+func (a responseGenerator) streamFieldList(sw *streamWriter, sel selection, ctx interface{}, path []interface{}, branchRefVal reflect.Value, deep uint64, exec *execution) error {
+	list, pending, err := a.findList(sel, ctx, path, branchRefVal, deep, exec)
+	if err != nil {
+		return err
+	}
+
+	return a.streamList(sw, sel, ctx, appendPath(path, sel.key), list, pending, nil, deep, exec)
+}
+
+// Writes objects of list field (received by "findList" or "resolveList") to the stream
+func (a responseGenerator) streamList(sw *streamWriter, sel selection, ctx interface{}, fieldPath []interface{}, list interface{}, pending *pendingField, children *GeneratedType, deep uint64, exec *execution) error {
+	// Deferred values are received after all objects, so loaders load them in batches
+	if pending != nil {
+		sw.pending(pending, exec)
+		return nil
+	}
+
+	elements, ok := listObjects(list, children)
+
+	// Object field
+	if !ok {
+		ds := fieldObject(list, children)
+		if ds == nil {
+			sw.write("null")
+			return nil
+		}
+
+		written := sw.written
+		if err := a.streamObject(sw, sel.fields, ctx, fieldPath, ds, deep+1, exec); err != nil {
+			if sw.err != nil || sw.written != written {
+				return err
+			}
+
+			if err := a.fieldError(exec, fieldPath, err); err != nil {
+				return err
+			}
+
+			sw.write("null")
+		}
+
+		return nil
+	}
+
+	sw.write("[")
+
+	for i, element := range elements {
+		if sw.err != nil {
+			return sw.err
+		}
+
+		if i != 0 {
+			sw.write(",")
+		}
+
+		elementPath := appendPath(fieldPath, i)
+		written := sw.written
+
+		// Nil pointers are written as null
+		if element == nil {
+			sw.write("null")
+			continue
+		}
+
+		if err := a.streamObject(sw, sel.fields, ctx, elementPath, element, deep+1, exec); err != nil {
+			// The object can't be replaced by null when it's already started
+			if sw.err != nil || sw.written != written {
+				return err
+			}
+
+			// The whole object becomes null if its "Resolve" method fails
+			if err := a.fieldError(exec, elementPath, err); err != nil {
+				return err
+			}
+
+			sw.write("null")
+		}
+	}
+
+	sw.write("]")
+
+	return nil
+}
+
+// Processes a request body like "Generate" but writes the JSON result to the writer while processing it, the written result is the same as "Generate" returns.
+// Deferred values (of loaders) are received in waves like in "Generate", so the part of the result after the first deferred value is kept in memory until they are received.
+// In the parallel mode the result is built in memory and written after it (goroutines finish in any order).
+// The writer can already contain a part of the result when an error is returned
+func (a responseGenerator) GenerateTo(w io.Writer, requestBody []interface{}, dataStruct interface{}, initContext map[string]interface{}) error {
+	return a.generateTo(w, requestBody, dataStruct, &initContext)
+}
+
+func (a responseGenerator) generateTo(w io.Writer, requestBody []interface{}, dataStruct interface{}, ctx interface{}) error {
+	// dataStruct argument must be Struct
+	if !isObject(dataStruct) {
+		return fmt.Errorf("dataStruct argument must be instance of struct or pointer to struct")
+	}
+
+	exec := a.newExecution()
+	sw := newStreamWriter(w, a.Config.StreamFlushSize)
+
+	if a.Config.Parallel {
+		result, err := a.run(exec, func(exec *execution) (interface{}, error) {
+			return a.recursiveGenerateResponse(requestBody, ctx, []interface{}{}, dataStruct, 1, exec)
+		})
+		if err != nil {
+			return err
+		}
+
+		var out interface{} = result.Data
+		if a.enveloped() {
+			out = result
+		}
+
+		if err := sw.value(out); err != nil {
+			return err
+		}
+
+		sw.flush()
+		return sw.err
+	}
+
+	if a.enveloped() {
+		sw.write(`{"data":`)
+	}
+
+	written := sw.written
+
+	// Start recursion to process all fields in the request
+	err := a.streamObject(sw, requestBody, ctx, []interface{}{}, dataStruct, 1, exec)
+	if err == nil {
+		if err := a.resolvePending(exec); err != nil {
+			return err
+		}
+
+		if err := sw.release(); err != nil {
+			return err
+		}
+	} else {
+		if sw.err != nil || sw.written != written {
+			return err
+		}
+
+		// The root "Resolve" method failed, so there is no data at all
+		if err := a.fieldError(exec, []interface{}{}, err); err != nil {
+			return err
+		}
+		sw.write("null")
+	}
+
+	if a.enveloped() {
+		if len(exec.errors) != 0 {
+			sw.write(`,"errors":`)
+			if err := sw.value(exec.errors); err != nil {
+				return err
+			}
+		}
+
+		if extensions := a.extensions(exec); extensions != nil {
+			sw.write(`,"extensions":`)
+			if err := sw.value(extensions); err != nil {
+				return err
+			}
+		}
+
+		sw.write("}")
+	}
+
+	sw.flush()
+
+	return sw.err
+}
@@ -0,0 +1,182 @@
+package hypeql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamSameAsGenerate(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+
+	tests := []struct {
+		config     ResponseGeneratorConfig
+		query      string
+		dataStruct any
+	}{
+		{ResponseGeneratorConfig{}, "{foo,bar,blist{text},clist{text,foo}}", A{}},
+		{ResponseGeneratorConfig{PartialResults: true}, "{name,broken,items{id}}", Partial{Name: "<partial>", Items: []Item{{Id: 1}, {Id: 2}}}},
+		{ResponseGeneratorConfig{PartialResults: true}, "{value}", ThrowsError{}},
+		{ResponseGeneratorConfig{}, "{products{id,title,price}}", Catalog{}},
+	}
+
+	for _, test := range tests {
+		generator := NewResponseGenerator(test.config)
+
+		parsed, err := parser.Parse(test.query)
+		if err != nil {
+			t.Fatal("Parsing error: " + err.Error())
+		}
+
+		mustBe, err := generator.Generate(parsed, test.dataStruct, map[string]any{})
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		buf := bytes.Buffer{}
+		if err := generator.GenerateTo(&buf, parsed, test.dataStruct, map[string]any{}); err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		if buf.String() != mustBe {
+			t.Fatal("Not equal: " + buf.String())
+		}
+	}
+}
+
+func TestStreamFlush(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		StreamFlushSize: 64,
+	})
+
+	recorder := httptest.NewRecorder()
+	if err := generator.GenerateTo(recorder, []any{[]any{"products", []any{"id"}}}, Catalog{}, map[string]any{}); err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if !recorder.Flushed || recorder.Body.Len() == 0 {
+		t.Fatal("Not flushed")
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+
+	err := generator.GenerateTo(&bytes.Buffer{}, []any{"value"}, ThrowsError{}, map[string]any{})
+	if err == nil || err.Error() != "Error throws here" {
+		t.Fatal("Not equal")
+	}
+}
+
+type Journal struct {
+	Issues []Issue `json:"issues"`
+}
+
+type Issue struct {
+	Id       int              `json:"id"`
+	Title    string           `json:"title" fun:"Rtitle"`
+	Editor   string           `json:"editor" fun:"Reditor"`
+	Articles []JournalArticle `json:"articles" fun:"Rarticles"`
+}
+
+type JournalArticle struct {
+	Id     int    `json:"id"`
+	Author string `json:"author" fun:"Rauthor"`
+}
+
+// Deferred value that fails
+type lostValue struct{}
+
+func (a lostValue) Value() (interface{}, error) {
+	return nil, errors.New("value is lost")
+}
+
+func (a Issue) Rtitle(ctx *map[string]any) (string, error) {
+	if a.Id == 2 {
+		return "", errors.New("title is lost")
+	}
+
+	return fmt.Sprint("Issue #", a.Id), nil
+}
+
+func (a Issue) Reditor(ctx *map[string]any) any {
+	if a.Id == 3 {
+		return lostValue{}
+	}
+
+	return (*ctx)["editors"].(*Loader[int, string]).Load(a.Id)
+}
+
+func (a Issue) Rarticles(ctx *map[string]any) any {
+	return (*ctx)["articles"].(*Loader[int, []JournalArticle]).Load(a.Id)
+}
+
+func (a JournalArticle) Rauthor(ctx *map[string]any) any {
+	if a.Id == 21 {
+		return lostValue{}
+	}
+
+	return (*ctx)["editors"].(*Loader[int, string]).Load(a.Id)
+}
+
+// Context with loaders that count their batches
+func journalContext(batches *int) map[string]any {
+	return map[string]any{
+		"editors": NewLoader(func(keys []int) ([]string, error) {
+			*batches++
+
+			values := []string{}
+			for _, key := range keys {
+				values = append(values, fmt.Sprint("Person #", key))
+			}
+
+			return values, nil
+		}),
+		"articles": NewLoader(func(keys []int) ([][]JournalArticle, error) {
+			*batches++
+
+			values := [][]JournalArticle{}
+			for _, key := range keys {
+				values = append(values, []JournalArticle{{Id: key * 10}, {Id: key*10 + 1}})
+			}
+
+			return values, nil
+		}),
+	}
+}
+
+func TestStreamLoaders(t *testing.T) {
+	parsed, err := NewQueryParser(QueryParserConfig{}).Parse("{issues{id,editor,articles{id,author},title}}")
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	journal := Journal{Issues: []Issue{{Id: 1}, {Id: 2}, {Id: 3}}}
+
+	for _, config := range []ResponseGeneratorConfig{{PartialResults: true}, {PartialResults: true, Parallel: true}, {PartialResults: true, StreamFlushSize: 1}} {
+		generator := NewResponseGenerator(config)
+
+		batches := 0
+		mustBe, err := generator.Generate(parsed, journal, journalContext(&batches))
+		if err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		buf := bytes.Buffer{}
+		streamBatches := 0
+		if err := generator.GenerateTo(&buf, parsed, journal, journalContext(&streamBatches)); err != nil {
+			t.Fatal("Process error: " + err.Error())
+		}
+
+		if buf.String() != mustBe {
+			t.Fatal("Not equal: " + buf.String() + "\n" + mustBe)
+		}
+
+		// Editors and articles in the first wave, authors of articles in the second one
+		if batches != 3 || streamBatches != 3 {
+			t.Fatal("Not equal: ", batches, streamBatches)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package hypeql
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Registry of custom scalar types (Go types that are written to the response with serialize functions and read from arguments with parse functions).
+// Types that aren't registered are written with encoding/json, so json.Marshaler and encoding.TextMarshaler are honored by default,
+// and arguments of types that implement encoding.TextUnmarshaler are parsed from strings
+type ScalarRegistry struct {
+	scalars     map[reflect.Type]*scalar
+	definitions sync.Map // Definitions of arguments structs by their types (cleared by "RegisterScalar")
+}
+
+type scalar struct {
+	name      string
+	serialize func(value reflect.Value) (interface{}, error)
+	parse     func(value interface{}) (reflect.Value, error) // Nil if the scalar can't be used as an argument
+}
+
+// Creates an empty registry of custom scalar types
+func NewScalarRegistry() *ScalarRegistry {
+	return &ScalarRegistry{
+		scalars: map[reflect.Type]*scalar{},
+	}
+}
+
+// Registers the Go type as a scalar with the name (for the introspection).
+// Serialize function converts values of fields to values of the response (nil to use encoding/json),
+// parse function converts values of arguments (int or string from the query) to the type (nil if the type can't be used as an argument)
+func RegisterScalar[T any](registry *ScalarRegistry, name string, serialize func(value T) (interface{}, error), parse func(value interface{}) (T, error)) {
+	s := &scalar{
+		name: name,
+		serialize: func(value reflect.Value) (interface{}, error) {
+			if serialize == nil {
+				return value.Interface(), nil
+			}
+
+			return serialize(value.Interface().(T))
+		},
+	}
+
+	if parse != nil {
+		s.parse = func(value interface{}) (reflect.Value, error) {
+			parsed, err := parse(value)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			return reflect.ValueOf(&parsed).Elem(), nil
+		}
+	}
+
+	registry.scalars[reflect.TypeFor[T]()] = s
+
+	// Arguments of the type are read differently now
+	registry.definitions.Range(func(key, value interface{}) bool {
+		registry.definitions.Delete(key)
+		return true
+	})
+}
+
+// Returns the registered scalar of the type (the registry can be nil)
+func (a *ScalarRegistry) lookup(t reflect.Type) *scalar {
+	if a == nil {
+		return nil
+	}
+
+	return a.scalars[t]
+}
+
+// Converts the value of a field (or elements of a slice) with the serialize function of its scalar type
+func (a *ScalarRegistry) serialize(value interface{}, path []interface{}) (interface{}, error) {
+	if a == nil || value == nil {
+		return value, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if s := a.lookup(v.Type()); s != nil {
+		return a.serializeValue(s, v, path)
+	}
+
+	// Pointers to values of scalar types
+	if v.Kind() == reflect.Pointer {
+		if s := a.lookup(v.Type().Elem()); s != nil {
+			if v.IsNil() {
+				return nil, nil
+			}
+
+			return a.serializeValue(s, v.Elem(), path)
+		}
+	}
+
+	// Slices of scalar types are serialized element by element
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && a.lookup(v.Type().Elem()) != nil {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+
+		s := a.lookup(v.Type().Elem())
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			var err error
+			if values[i], err = a.serializeValue(s, v.Index(i), appendPath(path, i)); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	}
+
+	return value, nil
+}
+
+func (a *ScalarRegistry) serializeValue(s *scalar, v reflect.Value, path []interface{}) (interface{}, error) {
+	value, err := s.serialize(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s serialization failed: %s", pathString(path), s.name, err.Error())
+	}
+
+	return value, nil
+}
+
+// Returns true if values of the type can be parsed from arguments by the registered scalar or encoding.TextUnmarshaler
+func (a *ScalarRegistry) parsable(t reflect.Type) bool {
+	if s := a.lookup(t); s != nil {
+		return s.parse != nil
+	}
+
+	return t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// Converts the argument's value to the type by the registered scalar or encoding.TextUnmarshaler
+func (a *ScalarRegistry) parse(value interface{}, t reflect.Type) (reflect.Value, error) {
+	if s := a.lookup(t); s != nil {
+		return s.parse(value)
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("must be string, but it's %T", value)
+	}
+
+	result := reflect.New(t)
+	if err := result.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		return reflect.Value{}, fmt.Errorf("is invalid: %s", err.Error())
+	}
+
+	return result.Elem(), nil
+}
+
+// Returns the name of the scalar for the Go type (registered name, name of the Go type that implements json.Marshaler or encoding.TextMarshaler, or the basic name)
+func (a *ScalarRegistry) name(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if s := a.lookup(t); s != nil {
+		return s.name
+	}
+
+	for _, marshaler := range []reflect.Type{reflect.TypeFor[json.Marshaler](), reflect.TypeFor[encoding.TextMarshaler]()} {
+		if t.Name() != "" && (t.Implements(marshaler) || reflect.PointerTo(t).Implements(marshaler)) {
+			return t.Name()
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "String"
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int"
+	case reflect.Float32, reflect.Float64:
+		return "Float"
+	}
+
+	return "Any"
+}
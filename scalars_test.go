@@ -0,0 +1,150 @@
+package hypeql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type Money struct {
+	Cents    int
+	Currency string
+}
+
+type Shop struct {
+	Orders []Order `json:"orders" fun:"Rorders"`
+}
+
+type OrdersArgs struct {
+	Since time.Time `arg:"since"`
+	Min   *Money    `arg:"min"`
+}
+
+func (a Shop) Rorders(ctx *map[string]any, args OrdersArgs) []Order {
+	minCents := 0
+	if args.Min != nil {
+		minCents = args.Min.Cents
+	}
+
+	return []Order{
+		{Created: args.Since, Total: Money{Cents: 1250 + minCents, Currency: "USD"}, Parts: []Money{{100, "USD"}, {5, "EUR"}}},
+	}
+}
+
+type Order struct {
+	Created time.Time `json:"created"`
+	Total   Money     `json:"total"`
+	Parts   []Money   `json:"parts"`
+	Refund  *Money    `json:"refund"`
+	Invalid Money     `json:"invalid" fun:"Rinvalid"`
+}
+
+func (a Order) Rinvalid(ctx *map[string]any) Money {
+	return Money{Cents: -1}
+}
+
+func newMoneyScalars() *ScalarRegistry {
+	scalars := NewScalarRegistry()
+	RegisterScalar(scalars, "Money", func(value Money) (any, error) {
+		if value.Cents < 0 {
+			return nil, errors.New("negative amount")
+		}
+
+		return fmt.Sprintf("%d.%02d %s", value.Cents/100, value.Cents%100, value.Currency), nil
+	}, func(value any) (Money, error) {
+		cents, ok := value.(int)
+		if !ok {
+			return Money{}, fmt.Errorf("must be cents, but it's %T", value)
+		}
+
+		return Money{Cents: cents, Currency: "USD"}, nil
+	})
+
+	return scalars
+}
+
+func TestScalars(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Scalars:        newMoneyScalars(),
+		PartialResults: true,
+	})
+	parser := NewQueryParser(QueryParserConfig{})
+
+	body, err := parser.Parse(`{orders(since: "2024-05-01T12:00:00Z", min: 50){created,total,parts,refund,invalid}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(body, Shop{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"data":{"orders":[{"created":"2024-05-01T12:00:00Z","total":"13.00 USD","parts":["1.00 USD","0.05 EUR"],"refund":null,"invalid":null}]},"errors":[{"message":"orders.0.invalid: Money serialization failed: negative amount","path":["orders",0,"invalid"]}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	body, err = parser.Parse(`{orders(since: "yesterday", min: "10$"){total}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err = generator.Generate(body, Shop{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	// Both invalid arguments are reported
+	mustBe := `{"data":{"orders":null},"errors":[{"message":"orders.since is invalid: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"; orders.min must be cents, but it's string","path":["orders"],"extensions":{"code":"BAD_USER_INPUT","violations":[` +
+		`{"argument":"orders.since","message":"is invalid: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\""},` +
+		`{"argument":"orders.min","message":"must be cents, but it's string"}]}}]}`
+
+	if resp != mustBe {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	schema, err := generator.Introspect(Shop{})
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	order := schema.Type("Order")
+	if order.Field("created").Type != "Time" || order.Field("total").Type != "Money" || order.Field("parts").Type != "Money" || order.Field("parts").Object {
+		t.Fatal("Not equal")
+	}
+
+	arguments := schema.Type("Shop").Field("orders").Arguments
+	if arguments[0].Type != "Time" || arguments[1].Type != "Money" {
+		t.Fatal("Not equal")
+	}
+
+	// Money can't be an argument without the registered parse function
+	if err := NewResponseGenerator(ResponseGeneratorConfig{}).Validate(Shop{}); err == nil {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestScalarRegisteredLater(t *testing.T) {
+	scalars := NewScalarRegistry()
+	generator := NewResponseGenerator(ResponseGeneratorConfig{Scalars: scalars})
+	body := []any{[]any{"orders", []any{"total"}, map[string]any{"since": "2024-05-01T12:00:00Z", "min": 50}}}
+
+	if _, err := generator.Generate(body, Shop{}, map[string]any{}); err == nil || err.Error() != "OrdersArgs.Min: unsupported argument type *hypeql.Money" {
+		t.Fatal("Not equal: ", err)
+	}
+
+	// The setup error isn't cached
+	RegisterScalar(scalars, "Money", nil, func(value any) (Money, error) {
+		return Money{Cents: value.(int), Currency: "USD"}, nil
+	})
+
+	resp, err := generator.Generate(body, Shop{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"orders":[{"total":{"Cents":1300,"Currency":"USD"}}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+}
@@ -21,7 +21,20 @@ type responseGenerator struct {
 }
 
 type ResponseGeneratorConfig struct {
-	MaxDeepRecursion uint64 // Stay 0 if unlimited
+	MaxDeepRecursion uint64          // Stay 0 if unlimited
+	PartialResults   bool            // Return {"data": ..., "errors": [...]} where failed fields are null instead of failing the whole response
+	Parallel         bool            // Resolve sibling fields and list elements concurrently (every new goroutine receives its own copy of the context map)
+	MaxGoroutines    uint64          // Limits goroutines of the parallel mode, stay 0 if unlimited
+	StreamFlushSize  uint64          // "GenerateTo" flushes the writer (http.ResponseWriter, for example) after every N bytes, stay 0 to flush only at the end
+	Authorizer       Authorizer      // Checks permissions of fields with the "auth" tag, stay nil to use RolesAuthorizer
+	Middlewares      []Middleware    // Wrap calls of resolver functions and "Resolve" methods, the first one is the outermost
+	Tracing          bool            // Record timings of calls and write them to the "extensions.tracing" object of the response
+	Debug            bool            // Write stack traces of panicked resolvers to the "extensions" object of their errors
+	Scalars          *ScalarRegistry // Custom scalar types of fields and arguments (nil if not used)
+	IgnoreGenerated  bool            // Use reflection for types with generated executors (see cmd/hypeql-gen)
+
+	// Called when a resolver function or "Resolve" method panics (the panic is converted to the error of the field)
+	PanicHandler func(info ResolveInfo, err *PanicError)
 }
 
 func NewResponseGenerator(config ResponseGeneratorConfig) responseGenerator {
@@ -0,0 +1,150 @@
+package hypeql
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Timings of resolver functions and "Resolve" methods called while processing a request.
+// It's written to the "extensions.tracing" object of the response in the tracing mode
+type Trace struct {
+	Version   int            `json:"version"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+	Duration  time.Duration  `json:"duration"` // Nanoseconds
+	Execution TraceExecution `json:"execution"`
+}
+
+type TraceExecution struct {
+	Resolvers []*TraceResolver `json:"resolvers"`
+}
+
+// Timings of a single call
+type TraceResolver struct {
+	Path        []interface{} `json:"path"`
+	ParentType  string        `json:"parentType"`  // Name of the struct whose method is called
+	FieldName   string        `json:"fieldName"`   // Empty for "Resolve" methods
+	Method      string        `json:"method"`      // Name of the called method
+	StartOffset time.Duration `json:"startOffset"` // Nanoseconds from the start of the request
+	Duration    time.Duration `json:"duration"`    // Nanoseconds
+}
+
+// Collects timings of a request (calls can be recorded from many goroutines in the parallel mode)
+type tracer struct {
+	mu    sync.Mutex
+	trace *Trace
+}
+
+func newTracer() *tracer {
+	return &tracer{
+		trace: &Trace{
+			Version:   1,
+			StartTime: time.Now(),
+			Execution: TraceExecution{
+				Resolvers: []*TraceResolver{},
+			},
+		},
+	}
+}
+
+// Wraps the call to record its timings
+func (a *tracer) wrap(call ResolveFunc) ResolveFunc {
+	return func(info ResolveInfo) (interface{}, error) {
+		start := time.Now()
+		result, err := call(info)
+		duration := time.Since(start)
+
+		parentType := ""
+		if info.Parent != nil {
+			parentType = reflect.Indirect(reflect.ValueOf(info.Parent)).Type().Name()
+		}
+
+		a.mu.Lock()
+		a.trace.Execution.Resolvers = append(a.trace.Execution.Resolvers, &TraceResolver{
+			Path:        info.Path,
+			ParentType:  parentType,
+			FieldName:   info.Field,
+			Method:      info.Method,
+			StartOffset: start.Sub(a.trace.StartTime),
+			Duration:    duration,
+		})
+		a.mu.Unlock()
+
+		return result, err
+	}
+}
+
+// Finishes the trace and returns it
+func (a *tracer) finish() *Trace {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.trace.EndTime = time.Now()
+	a.trace.Duration = a.trace.EndTime.Sub(a.trace.StartTime)
+
+	// Calls of the parallel mode are recorded in the order they finished
+	slices.SortStableFunc(a.trace.Execution.Resolvers, func(x, y *TraceResolver) int {
+		return cmp.Compare(x.StartOffset, y.StartOffset)
+	})
+
+	return a.trace
+}
+
+// Single event of the Chrome trace event format
+type chromeTraceEvent struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"cat"`
+	Phase     string                 `json:"ph"`
+	Timestamp float64                `json:"ts"`  // Microseconds
+	Duration  float64                `json:"dur"` // Microseconds
+	ProcessId int                    `json:"pid"`
+	ThreadId  int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// Converts the trace to the Chrome trace event format (open it in chrome://tracing or https://ui.perfetto.dev).
+// Calls that overlap in time (in the parallel mode) are placed on different threads
+func (a *Trace) ChromeTrace() ([]byte, error) {
+	events := []chromeTraceEvent{}
+	threadEnds := []time.Duration{} // End of the last call of each thread
+
+	for _, resolver := range a.Execution.Resolvers {
+		// Finding the first thread that is free at the start of the call
+		thread := slices.IndexFunc(threadEnds, func(end time.Duration) bool {
+			return end <= resolver.StartOffset
+		})
+		if thread == -1 {
+			thread = len(threadEnds)
+			threadEnds = append(threadEnds, 0)
+		}
+		threadEnds[thread] = resolver.StartOffset + resolver.Duration
+
+		name := pathString(resolver.Path)
+		if name == "" {
+			name = resolver.Method
+		}
+
+		events = append(events, chromeTraceEvent{
+			Name:      name,
+			Category:  "resolver",
+			Phase:     "X",
+			Timestamp: float64(a.StartTime.UnixNano()+int64(resolver.StartOffset)) / 1000,
+			Duration:  float64(resolver.Duration) / 1000,
+			ProcessId: 1,
+			ThreadId:  thread + 1,
+			Args: map[string]interface{}{
+				"parentType": resolver.ParentType,
+				"method":     resolver.Method,
+			},
+		})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
@@ -0,0 +1,98 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Tracing: true,
+	})
+
+	query := []any{"bar", []any{"clist", []any{"text"}}}
+
+	result, err := generator.GenerateResult(query, A{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	trace := result.Trace()
+	if trace == nil || trace.Duration <= 0 {
+		t.Fatal("No trace")
+	}
+
+	mustBe := []string{"bar Rbar", "clist Rclist", "clist.0 Resolve", "clist.0.text Rtext", "clist.1 Resolve", "clist.1.text Rtext"}
+	if len(trace.Execution.Resolvers) != len(mustBe) {
+		t.Fatal("Not equal")
+	}
+
+	for i, resolver := range trace.Execution.Resolvers {
+		if pathString(resolver.Path)+" "+resolver.Method != mustBe[i] {
+			t.Fatal("Not equal: " + pathString(resolver.Path))
+		}
+	}
+
+	// The response has the tracing object
+	resp, err := generator.Generate(query, A{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	parsed := struct {
+		Data       map[string]any `json:"data"`
+		Extensions struct {
+			Tracing Trace `json:"tracing"`
+		} `json:"extensions"`
+	}{}
+	if err := json.Unmarshal([]byte(resp), &parsed); err != nil {
+		t.Fatal("JSON error: " + err.Error())
+	}
+
+	if parsed.Data["bar"] != true || len(parsed.Extensions.Tracing.Execution.Resolvers) != len(mustBe) {
+		t.Fatal("Not equal: " + resp)
+	}
+}
+
+func TestChromeTrace(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		Tracing:  true,
+		Parallel: true,
+	})
+
+	result, err := generator.GenerateResult([]any{[]any{"products", []any{"title", "price"}}}, Catalog{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	out, err := result.Trace().ChromeTrace()
+	if err != nil {
+		t.Fatal("Converting error: " + err.Error())
+	}
+
+	parsed := struct {
+		TraceEvents []struct {
+			Name  string `json:"name"`
+			Phase string `json:"ph"`
+			Tid   int    `json:"tid"`
+		} `json:"traceEvents"`
+	}{}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatal("JSON error: " + err.Error())
+	}
+
+	// Rproducts, 50 "Resolve" methods, 50 Rtitle and 50 Rprice calls
+	if len(parsed.TraceEvents) != 151 || parsed.TraceEvents[0].Name != "products" {
+		t.Fatal("Not equal")
+	}
+
+	// Parallel calls are placed on different threads
+	threads := map[int]bool{}
+	for _, event := range parsed.TraceEvents {
+		threads[event.Tid] = true
+	}
+
+	if len(threads) < 2 {
+		t.Fatal("No parallel threads")
+	}
+}
@@ -0,0 +1,167 @@
+package hypeql
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Generator whose resolver functions and "Resolve" methods receive the typed context (*C) instead of *map[string]interface{}
+type typedResponseGenerator[C any] struct {
+	generator responseGenerator
+}
+
+// Creates a generator with the typed context.
+// Resolvers of the passed response structs (and their nested structs) are checked to receive *C, so mismatched signatures are reported before processing requests
+func NewTypedResponseGenerator[C any](config ResponseGeneratorConfig, dataStructs ...interface{}) (typedResponseGenerator[C], error) {
+	a := typedResponseGenerator[C]{
+		generator: NewResponseGenerator(config),
+	}
+
+	for _, dataStruct := range dataStructs {
+		if err := a.Validate(dataStruct); err != nil {
+			return a, err
+		}
+	}
+
+	return a, nil
+}
+
+// Checks signatures of resolver functions and "Resolve" methods of the response struct and its nested structs
+func (a typedResponseGenerator[C]) Validate(dataStruct interface{}) error {
+	return checkResolvers(reflect.TypeOf(dataStruct), reflect.TypeFor[*C](), a.generator.Config.Scalars)
+}
+
+// Describes the response struct and its nested structs with custom scalar types of the generator
+func (a typedResponseGenerator[C]) Introspect(dataStruct interface{}) (*Schema, error) {
+	return a.generator.Introspect(dataStruct)
+}
+
+// Processes a request body and returns a result (the first is JSON string)
+func (a typedResponseGenerator[C]) Generate(requestBody []interface{}, dataStruct interface{}, ctx *C) (string, error) {
+	return a.generator.generate(requestBody, dataStruct, ctx)
+}
+
+// Processes a request body and returns a result as objects with the requested order of fields
+func (a typedResponseGenerator[C]) GenerateResult(requestBody []interface{}, dataStruct interface{}, ctx *C) (*Result, error) {
+	return a.generator.generateResult(requestBody, dataStruct, ctx)
+}
+
+// Processes a request body and writes the JSON result to the writer while processing it
+func (a typedResponseGenerator[C]) GenerateTo(w io.Writer, requestBody []interface{}, dataStruct interface{}, ctx *C) error {
+	return a.generator.generateTo(w, requestBody, dataStruct, ctx)
+}
+
+// Checks signatures of resolver functions and "Resolve" methods of the response struct and its nested structs
+func (a responseGenerator) Validate(dataStruct interface{}) error {
+	return checkResolvers(reflect.TypeOf(dataStruct), reflect.TypeFor[*map[string]interface{}](), a.Config.Scalars)
+}
+
+// Checks that resolvers of the struct type and its nested structs receive the context type
+func checkResolvers(t reflect.Type, ctxType reflect.Type, scalars *ScalarRegistry) error {
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("dataStruct argument must be instance of struct or pointer to struct")
+	}
+
+	return checkStructResolvers(t, ctxType, scalars, map[reflect.Type]bool{})
+}
+
+func checkStructResolvers(t reflect.Type, ctxType reflect.Type, scalars *ScalarRegistry, checked map[reflect.Type]bool) error {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Skipping already checked structs (structs can be nested recursively)
+	if t.Kind() != reflect.Struct || checked[t] {
+		return nil
+	}
+	checked[t] = true
+
+	// Method set of the pointer has methods with both value and pointer receivers
+	methods := reflect.PointerTo(t)
+
+	if method, ok := methods.MethodByName("Resolve"); ok {
+		if err := checkMethod(t, method, []reflect.Type{ctxType, reflect.TypeFor[[]string]()}, true); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Tag.Get("json") == "" || sf.Type.Kind() == reflect.Func {
+			continue
+		}
+
+		if funcName := sf.Tag.Get("fun"); funcName != "" {
+			// Fields without the resolver function take the field value
+			if method, ok := methods.MethodByName(funcName); ok {
+				in := []reflect.Type{ctxType}
+
+				// Resolver function can also receive arguments (map or arguments struct)
+				if method.Type.NumIn() == 3 {
+					argsType := reflect.TypeFor[map[string]interface{}]()
+
+					if isArgumentsType(method.Type.In(2)) {
+						argsType = method.Type.In(2)
+						if _, err := argumentDefinitions(argsType, scalars); err != nil {
+							return err
+						}
+					}
+
+					in = append(in, argsType)
+				}
+
+				if err := checkMethod(t, method, in, false); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Checking structs of list and object fields
+		if sf.Type.Kind() == reflect.Slice {
+			if err := checkStructResolvers(sf.Type.Elem(), ctxType, scalars, checked); err != nil {
+				return err
+			}
+		} else if isObjectType(sf.Type, scalars) {
+			if err := checkStructResolvers(sf.Type, ctxType, scalars, checked); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Checks arguments and returned values of the method.
+// "Resolve" methods can return only an error, resolver functions can return a value and an error
+func checkMethod(t reflect.Type, method reflect.Method, in []reflect.Type, isResolve bool) error {
+	mt := method.Type // The first argument is the receiver
+
+	valid := mt.NumIn() == len(in)+1
+	for i := 0; valid && i < len(in); i++ {
+		valid = mt.In(i+1) == in[i]
+	}
+
+	if !valid {
+		mustBe := []string{}
+		for _, arg := range in {
+			mustBe = append(mustBe, arg.String())
+		}
+
+		return fmt.Errorf("%s.%s must receive (%s), but it's %s", t.Name(), method.Name, strings.Join(mustBe, ", "), mt.String())
+	}
+
+	errorType := reflect.TypeFor[error]()
+	switch {
+	case isResolve && (mt.NumOut() > 1 || mt.NumOut() == 1 && mt.Out(0) != errorType):
+		return fmt.Errorf("%s.%s must return nothing or an error, but it's %s", t.Name(), method.Name, mt.String())
+	case mt.NumOut() > 2 || mt.NumOut() == 2 && mt.Out(1) != errorType:
+		return fmt.Errorf("%s.%s must return a value and optionally an error, but it's %s", t.Name(), method.Name, mt.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package hypeql
+
+import (
+	"strings"
+	"testing"
+)
+
+type Session struct {
+	User   string
+	Visits int
+}
+
+type Account struct {
+	Name    string    `json:"name" fun:"Rname"`
+	Visits  int       `json:"visits"`
+	Friends []Account `json:"friends" fun:"Rfriends"`
+}
+
+func (a *Account) Resolve(ctx *Session, fields []string) {
+	ctx.Visits++
+	a.Visits = ctx.Visits
+}
+
+func (a Account) Rname(ctx *Session) string {
+	if a.Name == "" {
+		return ctx.User
+	}
+
+	return a.Name
+}
+
+func (a Account) Rfriends(ctx *Session, args map[string]any) ([]Account, error) {
+	return []Account{{Name: "Bob"}, {Name: "Alice"}}, nil
+}
+
+type WrongContext struct {
+	Value string `json:"value" fun:"Rvalue"`
+}
+
+func (a WrongContext) Rvalue(ctx *map[string]any) string {
+	return "value"
+}
+
+type WrongNested struct {
+	Items []WrongReturn `json:"items"`
+}
+
+type WrongReturn struct {
+	Value string `json:"value" fun:"Rvalue"`
+}
+
+func (a WrongReturn) Rvalue(ctx *Session) (string, string) {
+	return "value", ""
+}
+
+func TestTypedContext(t *testing.T) {
+	generator, err := NewTypedResponseGenerator[Session](ResponseGeneratorConfig{}, Account{})
+	if err != nil {
+		t.Fatal("Setup error: " + err.Error())
+	}
+
+	session := &Session{User: "John"}
+	resp, err := generator.Generate([]any{"name", "visits", []any{"friends", []any{"name", "visits"}}}, Account{}, session)
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"name":"John","visits":1,"friends":[{"name":"Bob","visits":2},{"name":"Alice","visits":3}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	if session.Visits != 3 {
+		t.Fatal("Not equal")
+	}
+}
+
+func TestTypedContextValidation(t *testing.T) {
+	_, err := NewTypedResponseGenerator[Session](ResponseGeneratorConfig{}, WrongContext{})
+	if err == nil || !strings.HasPrefix(err.Error(), "WrongContext.Rvalue must receive (*hypeql.Session)") {
+		t.Fatal("Not equal")
+	}
+
+	_, err = NewTypedResponseGenerator[Session](ResponseGeneratorConfig{}, &WrongNested{})
+	if err == nil || !strings.HasPrefix(err.Error(), "WrongReturn.Rvalue must return a value and optionally an error") {
+		t.Fatal("Not equal")
+	}
+
+	// Untyped generator checks resolvers to receive *map[string]interface{}
+	generator := NewResponseGenerator(ResponseGeneratorConfig{})
+	if generator.Validate(WrongContext{}) != nil || generator.Validate(Account{}) == nil {
+		t.Fatal("Not equal")
+	}
+
+	if generator.Validate(1) == nil {
+		t.Fatal("Not equal")
+	}
+}
@@ -0,0 +1,286 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Writes TypeScript types of the schema (returned by Introspect or served to the explorer) and results of queries
+type typeScriptGenerator struct {
+	Config TypeScriptConfig
+}
+
+type TypeScriptConfig struct {
+	Scalars map[string]string // TypeScript types of custom scalars by their names ("Time": "string", for example), stay nil to write them as "unknown"
+	Indent  string            // Indentation of fields, stay empty to use two spaces
+}
+
+func NewTypeScriptGenerator(config TypeScriptConfig) typeScriptGenerator {
+	return typeScriptGenerator{
+		Config: config,
+	}
+}
+
+// TypeScript types of built-in scalars
+var typeScriptScalars = map[string]string{
+	"String":  "string",
+	"Int":     "number",
+	"Float":   "number",
+	"Boolean": "boolean",
+	"Any":     "unknown",
+}
+
+// Returns interfaces of struct types of the schema and aliases of custom scalars
+func (a typeScriptGenerator) Types(schema *Schema) string {
+	var builder strings.Builder
+
+	for _, name := range customScalars(schema) {
+		fmt.Fprintf(&builder, "export type %s = %s;\n\n", name, a.scalar(name))
+	}
+
+	for i, t := range schema.Types {
+		if i != 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "export interface %s {\n", t.Name)
+		for _, field := range t.Fields {
+			fieldType := field.Type
+			if !field.Object {
+				fieldType = a.scalarType(field.Type)
+			}
+
+			a.writeField(&builder, 1, field, fieldType)
+		}
+		builder.WriteString("}\n")
+	}
+
+	return builder.String()
+}
+
+// Returns the interface of the query result (named by "name") with selected fields only, and the interface of its variables ("<name>Variables") if the query has them.
+// Types of fields requested without fields and custom scalars refer to types written by "Types"
+func (a typeScriptGenerator) Query(schema *Schema, name string, requestBody []interface{}) (string, error) {
+	root := schema.Type(schema.Root)
+	if root == nil {
+		return "", fmt.Errorf("root type " + schema.Root + " not found in the schema")
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "export interface %s ", name)
+	if err := a.writeSelection(&builder, schema, root, requestBody, []interface{}{}, 0); err != nil {
+		return "", err
+	}
+	builder.WriteString("\n")
+
+	variables := map[string]*SchemaArgument{}
+	if err := queryVariables(schema, root, requestBody, []interface{}{}, variables); err != nil {
+		return "", err
+	}
+
+	if len(variables) != 0 {
+		names := []string{}
+		for variable := range variables {
+			names = append(names, variable)
+		}
+		slices.Sort(names)
+
+		fmt.Fprintf(&builder, "\nexport interface %sVariables {\n", name)
+		for _, variable := range names {
+			optional := "?"
+			if variables[variable].Required {
+				optional = ""
+			}
+
+			fmt.Fprintf(&builder, "%s%s%s: %s;\n", a.indent(1), propertyName(variable), optional, a.scalarType(variables[variable].Type))
+		}
+		builder.WriteString("}\n")
+	}
+
+	return builder.String(), nil
+}
+
+// Writes selected fields of the object type in curly brackets
+func (a typeScriptGenerator) writeSelection(builder *strings.Builder, schema *Schema, t *SchemaType, fields []interface{}, path []interface{}, depth int) error {
+	builder.WriteString("{\n")
+
+	for _, item := range fields {
+		key, children := "", []interface{}(nil)
+		switch item := item.(type) {
+		case string:
+			key = item
+		case []interface{}:
+			key, _ = item[0].(string)
+			children, _ = item[1].([]interface{})
+		}
+
+		fieldPath := appendPath(path, key)
+		field := t.Field(key)
+		if field == nil {
+			return fmt.Errorf(pathString(fieldPath) + " not found in the schema")
+		}
+
+		if children == nil {
+			// Objects requested without fields are written entirely
+			fieldType := field.Type
+			if !field.Object {
+				fieldType = a.scalarType(field.Type)
+			}
+
+			a.writeField(builder, depth+1, field, fieldType)
+			continue
+		}
+
+		child := schema.Type(field.Type)
+		if !field.Object || child == nil {
+			return fmt.Errorf(pathString(fieldPath) + " isn't an object, it can't be requested with fields")
+		}
+
+		var selection strings.Builder
+		if err := a.writeSelection(&selection, schema, child, children, fieldPath, depth+1); err != nil {
+			return err
+		}
+
+		a.writeField(builder, depth+1, field, selection.String())
+	}
+
+	builder.WriteString(a.indent(depth) + "}")
+
+	return nil
+}
+
+// Writes the field with its type, values that can be null have the "| null" type ("unknown" includes null)
+func (a typeScriptGenerator) writeField(builder *strings.Builder, depth int, field *SchemaField, fieldType string) {
+	if field.List {
+		if field.NullableItems && fieldType != "unknown" {
+			fieldType += " | null"
+		}
+
+		fieldType = "Array<" + fieldType + ">"
+	}
+
+	if field.Nullable && fieldType != "unknown" {
+		fieldType += " | null"
+	}
+
+	fmt.Fprintf(builder, "%s%s: %s;\n", a.indent(depth), propertyName(field.Name), fieldType)
+}
+
+// Returns the TypeScript type of the scalar (the name of the alias for custom scalars)
+func (a typeScriptGenerator) scalarType(name string) string {
+	if t, ok := typeScriptScalars[name]; ok {
+		return t
+	}
+
+	return name
+}
+
+// Returns the type of the custom scalar alias
+func (a typeScriptGenerator) scalar(name string) string {
+	if t, ok := a.Config.Scalars[name]; ok {
+		return t
+	}
+
+	return "unknown"
+}
+
+func (a typeScriptGenerator) indent(depth int) string {
+	indent := a.Config.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	return strings.Repeat(indent, depth)
+}
+
+// Returns sorted names of custom scalars used by fields and arguments of the schema
+func customScalars(schema *Schema) []string {
+	names := []string{}
+	add := func(name string) {
+		if _, ok := typeScriptScalars[name]; !ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, t := range schema.Types {
+		for _, field := range t.Fields {
+			if !field.Object {
+				add(field.Type)
+			}
+
+			for _, argument := range field.Arguments {
+				add(argument.Type)
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Finds arguments that receive variables ("$name") in the request body
+func queryVariables(schema *Schema, t *SchemaType, fields []interface{}, path []interface{}, variables map[string]*SchemaArgument) error {
+	for _, item := range fields {
+		branch, ok := item.([]interface{})
+		if !ok || len(branch) < 2 {
+			continue
+		}
+
+		key, _ := branch[0].(string)
+		field := t.Field(key)
+		if field == nil {
+			continue
+		}
+
+		if len(branch) > 2 {
+			arguments, _ := branch[2].(map[string]interface{})
+			for name, value := range arguments {
+				if !isVariable(value) {
+					continue
+				}
+
+				argument := &SchemaArgument{Name: name, Type: "Any"}
+				for _, definition := range field.Arguments {
+					if definition.Name == name {
+						argument = definition
+					}
+				}
+
+				// Arguments of unknown types don't change types of variables
+				variable := string(value.(Variable))
+				existing, ok := variables[variable]
+				if ok && existing.Type != argument.Type && existing.Type != "Any" && argument.Type != "Any" {
+					return fmt.Errorf(pathString(appendPath(path, key)) + " receives the variable $" + variable + " of different types")
+				}
+
+				if !ok || existing.Type == "Any" || argument.Required && !existing.Required && argument.Type != "Any" {
+					variables[variable] = argument
+				}
+			}
+		}
+
+		if child := schema.Type(field.Type); field.Object && child != nil {
+			children, _ := branch[1].([]interface{})
+			if err := queryVariables(schema, child, children, appendPath(path, key), variables); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Quotes names that aren't identifiers
+func propertyName(name string) string {
+	if identifierRegexp.MatchString(name) {
+		return name
+	}
+
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
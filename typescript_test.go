@@ -0,0 +1,128 @@
+package hypeql
+
+import (
+	"testing"
+	"time"
+)
+
+type Theater struct {
+	Name   string    `json:"name"`
+	Opened time.Time `json:"opened"`
+	Shows  []Show    `json:"shows" fun:"Rshows" filter:"true"`
+	Tags   []string  `json:"tags"`
+	Stage  *Stage    `json:"stage"`
+	Halls  []*Stage  `json:"halls"`
+	Extra  any       `json:"extra-info"`
+}
+
+type ShowsArgs struct {
+	Limit  int     `arg:"limit"`
+	Offset *int    `arg:"offset"`
+	Title  string  `arg:"title" default:""`
+	Rating float64 `arg:"rating" default:"0"`
+}
+
+func (a Theater) Rshows(ctx *map[string]interface{}, args ShowsArgs) []Show {
+	return nil
+}
+
+type Show struct {
+	Title  string  `json:"title"`
+	Rating float64 `json:"rating"`
+	Sold   bool    `json:"sold"`
+	Stage  Stage   `json:"stage"`
+}
+
+type Stage struct {
+	Seats int `json:"seats"`
+}
+
+func TestTypeScriptTypes(t *testing.T) {
+	schema, err := Introspect(Theater{})
+	if err != nil {
+		t.Fatal("Introspection error: " + err.Error())
+	}
+
+	generator := NewTypeScriptGenerator(TypeScriptConfig{Scalars: map[string]string{"Time": "string"}})
+
+	expected := `export type Time = string;
+
+export interface Theater {
+  name: string;
+  opened: Time;
+  shows: Array<Show> | null;
+  tags: Array<string> | null;
+  stage: Stage | null;
+  halls: Array<Stage | null> | null;
+  "extra-info": unknown;
+}
+
+export interface Show {
+  title: string;
+  rating: number;
+  sold: boolean;
+  stage: Stage;
+}
+
+export interface Stage {
+  seats: number;
+}
+`
+
+	if result := generator.Types(schema); result != expected {
+		t.Fatal("Not equal:\n" + result)
+	}
+}
+
+func TestTypeScriptQuery(t *testing.T) {
+	schema, _ := Introspect(Theater{})
+	parser := NewQueryParser(QueryParserConfig{})
+	generator := NewTypeScriptGenerator(TypeScriptConfig{Indent: "\t"})
+
+	parsed, err := parser.Parse(`{
+		name
+		shows(limit: $limit, offset: $offset, where: $where, orderBy: "title"){
+			title
+			stage{
+				seats
+			}
+		}
+		stage
+	}`)
+	if err != nil {
+		t.Fatal("Parsing error: " + err.Error())
+	}
+
+	expected := "export interface TheaterQuery {\n" +
+		"\tname: string;\n" +
+		"\tshows: Array<{\n" +
+		"\t\ttitle: string;\n" +
+		"\t\tstage: {\n" +
+		"\t\t\tseats: number;\n" +
+		"\t\t};\n" +
+		"\t}> | null;\n" +
+		"\tstage: Stage | null;\n" +
+		"}\n" +
+		"\n" +
+		"export interface TheaterQueryVariables {\n" +
+		"\tlimit: number;\n" +
+		"\toffset?: number;\n" +
+		"\twhere?: string;\n" +
+		"}\n"
+
+	result, err := generator.Query(schema, "TheaterQuery", parsed)
+	if err != nil {
+		t.Fatal("Generating error: " + err.Error())
+	}
+
+	if result != expected {
+		t.Fatal("Not equal:\n" + result)
+	}
+
+	for _, query := range []string{`{unknown}`, `{name{length}}`, `{shows{stage{rows}}}`} {
+		parsed, _ := parser.Parse(query)
+		if _, err := generator.Query(schema, "Q", parsed); err == nil {
+			t.Fatal("Not error: " + query)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package hypeql
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validation rules of an argument from the struct tags:
+// `min:"1" max:"100"` for numbers, `minLen:"3" maxLen:"20" pattern:"^[a-z]+$"` for strings, `enum:"asc,desc"` and `required:"true"`
+type argumentRules struct {
+	min      *float64       // Minimal value of a number
+	max      *float64       // Maximal value of a number
+	minLen   *int           // Minimal count of characters of a string
+	maxLen   *int           // Maximal count of characters of a string
+	pattern  *regexp.Regexp // Regular expression that a string must match
+	enum     []interface{}  // Allowed values (converted to the field's type)
+	required bool           // Argument must be passed and not be empty (zero)
+}
+
+// Error of arguments that violate validation rules (all violations of the field's arguments are listed)
+type ValidationError struct {
+	Violations []ArgumentViolation
+}
+
+// Violated validation rule of an argument
+type ArgumentViolation struct {
+	Argument string `json:"argument"` // Path of the argument (field's path and argument's name, e.g. "films.limit")
+	Message  string `json:"message"`
+}
+
+func (a *ValidationError) Error() string {
+	messages := []string{}
+	for _, violation := range a.Violations {
+		messages = append(messages, violation.Argument+" "+violation.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (a *ValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       "BAD_USER_INPUT",
+		"violations": a.Violations,
+	}
+}
+
+// Reads validation rules of the argument field (rules that can't be applied to the field's type are setup errors)
+func parseArgumentRules(sf reflect.StructField, fieldType reflect.Type, scalars *ScalarRegistry) (argumentRules, error) {
+	rules := argumentRules{}
+
+	// Rules of numbers and strings can't be used for custom scalar types
+	isCustom := !isArgumentKind(fieldType.Kind()) || scalars.lookup(fieldType) != nil
+	isNumber := !isCustom && fieldType.Kind() != reflect.String && fieldType.Kind() != reflect.Bool
+	isString := !isCustom && fieldType.Kind() == reflect.String
+
+	parseNumber := func(tag string, onlyFor bool, kind string) (*float64, error) {
+		value, ok := sf.Tag.Lookup(tag)
+		if !ok {
+			return nil, nil
+		}
+		if !onlyFor {
+			return nil, fmt.Errorf("%s rule can be used only for %s", tag, kind)
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s rule must be a number", tag)
+		}
+
+		return &number, nil
+	}
+
+	parseLength := func(tag string) (*int, error) {
+		number, err := parseNumber(tag, isString, "strings")
+		if number == nil || err != nil {
+			return nil, err
+		}
+
+		length := int(*number)
+		if float64(length) != *number || length < 0 {
+			return nil, fmt.Errorf("%s rule must be a non-negative integer", tag)
+		}
+
+		return &length, nil
+	}
+
+	var err error
+	if rules.min, err = parseNumber("min", isNumber, "numbers"); err != nil {
+		return rules, err
+	}
+	if rules.max, err = parseNumber("max", isNumber, "numbers"); err != nil {
+		return rules, err
+	}
+	if rules.minLen, err = parseLength("minLen"); err != nil {
+		return rules, err
+	}
+	if rules.maxLen, err = parseLength("maxLen"); err != nil {
+		return rules, err
+	}
+
+	if pattern, ok := sf.Tag.Lookup("pattern"); ok {
+		if !isString {
+			return rules, fmt.Errorf("pattern rule can be used only for strings")
+		}
+
+		if rules.pattern, err = regexp.Compile(pattern); err != nil {
+			return rules, fmt.Errorf("pattern rule is invalid: %s", err.Error())
+		}
+	}
+
+	if enum, ok := sf.Tag.Lookup("enum"); ok {
+		if !fieldType.Comparable() {
+			return rules, fmt.Errorf("enum rule can't be used for %s", fieldType.String())
+		}
+
+		for _, value := range strings.Split(enum, ",") {
+			converted, err := convertArgument(parseArgumentValue(value), fieldType, scalars)
+			if err != nil {
+				return rules, fmt.Errorf("enum value %s %s", value, err.Error())
+			}
+
+			rules.enum = append(rules.enum, converted.Interface())
+		}
+	}
+
+	if required, ok := sf.Tag.Lookup("required"); ok {
+		if rules.required, err = strconv.ParseBool(required); err != nil {
+			return rules, fmt.Errorf("required rule must be true or false")
+		}
+	}
+
+	return rules, nil
+}
+
+// Returns messages of violated rules of the argument's value (invalid value of nil pointer if the argument isn't passed)
+func (a argumentRules) check(value reflect.Value) []string {
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || value.IsZero() {
+		if a.required {
+			return []string{"is required"}
+		}
+
+		// Rules aren't checked for arguments that aren't passed
+		if !value.IsValid() {
+			return nil
+		}
+	}
+
+	violations := []string{}
+
+	var number float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		number = value.Float()
+	}
+
+	if a.min != nil && number < *a.min {
+		violations = append(violations, "must be at least "+strconv.FormatFloat(*a.min, 'f', -1, 64))
+	}
+	if a.max != nil && number > *a.max {
+		violations = append(violations, "must be at most "+strconv.FormatFloat(*a.max, 'f', -1, 64))
+	}
+
+	if value.Kind() == reflect.String {
+		length := utf8.RuneCountInString(value.String())
+
+		if a.minLen != nil && length < *a.minLen {
+			violations = append(violations, "must have at least "+strconv.Itoa(*a.minLen)+" characters")
+		}
+		if a.maxLen != nil && length > *a.maxLen {
+			violations = append(violations, "must have at most "+strconv.Itoa(*a.maxLen)+" characters")
+		}
+		if a.pattern != nil && !a.pattern.MatchString(value.String()) {
+			violations = append(violations, "must match "+a.pattern.String())
+		}
+	}
+
+	if a.enum != nil && !slices.Contains(a.enum, value.Interface()) {
+		allowed := []string{}
+		for _, enumValue := range a.enum {
+			allowed = append(allowed, fmt.Sprint(enumValue))
+		}
+
+		violations = append(violations, "must be one of "+strings.Join(allowed, ", "))
+	}
+
+	return violations
+}
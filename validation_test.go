@@ -0,0 +1,85 @@
+package hypeql
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type Forum struct {
+	Posts []Post `json:"posts" fun:"Rposts"`
+}
+
+type PostsArgs struct {
+	Limit  int     `arg:"limit" default:"10" min:"1" max:"50"`
+	Author *string `arg:"author" minLen:"3" maxLen:"10" pattern:"^[a-z]+$"`
+	Order  string  `arg:"order" default:"new" enum:"new,old,top"`
+	Rating float64 `arg:"rating" default:"0" enum:"0,2.5,5"`
+	Tag    *string `arg:"tag" required:"true"`
+}
+
+func (a Forum) Rposts(ctx *map[string]any, args *PostsArgs) []Post {
+	return []Post{{Title: *args.Tag + " " + args.Order}}
+}
+
+type Post struct {
+	Title string `json:"title"`
+}
+
+type InvalidRules struct {
+	Items []Post `json:"items" fun:"Ritems"`
+}
+
+type InvalidRulesArgs struct {
+	Count int `arg:"count" pattern:"[0-9]+"`
+}
+
+func (a InvalidRules) Ritems(ctx *map[string]any, args InvalidRulesArgs) []Post {
+	return []Post{}
+}
+
+func TestArgumentsValidation(t *testing.T) {
+	generator := NewResponseGenerator(ResponseGeneratorConfig{
+		PartialResults: true,
+	})
+	parser := NewQueryParser(QueryParserConfig{})
+
+	body, err := parser.Parse(`{posts(tag: "go", order: "top", rating: 2.5){title}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	resp, err := generator.Generate(body, Forum{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"data":{"posts":[{"title":"go top"}]}}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	body, err = parser.Parse(`{posts(limit: 0, author: "Jo", order: "hot", tag: ""){title}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	result, err := generator.GenerateResult(body, Forum{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	out, _ := json.Marshal(result)
+	mustBe := `{"data":{"posts":null},"errors":[{"message":"posts.limit must be at least 1; posts.author must have at least 3 characters; posts.author must match ^[a-z]+$; posts.order must be one of new, old, top; posts.tag is required","path":["posts"],"extensions":{"code":"BAD_USER_INPUT","violations":[` +
+		`{"argument":"posts.limit","message":"must be at least 1"},` +
+		`{"argument":"posts.author","message":"must have at least 3 characters"},` +
+		`{"argument":"posts.author","message":"must match ^[a-z]+$"},` +
+		`{"argument":"posts.order","message":"must be one of new, old, top"},` +
+		`{"argument":"posts.tag","message":"is required"}]}}]}`
+
+	if string(out) != mustBe {
+		t.Fatal("Not equal: " + string(out))
+	}
+
+	if err := generator.Validate(InvalidRules{}); err == nil || err.Error() != "InvalidRulesArgs.Count: pattern rule can be used only for strings" {
+		t.Fatal("Not equal: ", err)
+	}
+}
@@ -0,0 +1,123 @@
+package hypeql
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Returns a copy of the parsed request body where arguments written as variables ("$name") are replaced with values of the variables.
+// Numbers without a fraction become int (like numbers in the query), strings, bools and other numbers are kept, arguments of null variables are removed.
+// The parsed request body isn't changed, so it can be shared
+func ApplyVariables(requestBody []interface{}, variables map[string]interface{}) ([]interface{}, error) {
+	result := make([]interface{}, len(requestBody))
+
+	for i, item := range requestBody {
+		branch, ok := item.([]interface{})
+		if !ok || len(branch) < 2 {
+			result[i] = item
+			continue
+		}
+
+		fields, ok := branch[1].([]interface{})
+		if !ok {
+			result[i] = item
+			continue
+		}
+
+		newFields, err := ApplyVariables(fields, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		newBranch := []interface{}{branch[0], newFields}
+
+		if len(branch) > 2 {
+			arguments, ok := branch[2].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid arguments of %v", branch[0])
+			}
+
+			// Sorting names to report the same undefined variable every time
+			names := []string{}
+			for name := range arguments {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			newArguments := map[string]interface{}{}
+			for _, name := range names {
+				value := arguments[name]
+				if !isVariable(value) {
+					newArguments[name] = value
+					continue
+				}
+
+				text := "$" + string(value.(Variable))
+				variable, ok := variables[string(value.(Variable))]
+				if !ok {
+					return nil, fmt.Errorf("variable " + text + " is not defined")
+				}
+
+				if variable == nil {
+					continue
+				}
+
+				if newArguments[name], err = variableValue(variable); err != nil {
+					return nil, fmt.Errorf("variable " + text + " " + err.Error())
+				}
+			}
+
+			newBranch = append(newBranch, newArguments)
+		}
+
+		result[i] = newBranch
+	}
+
+	return result, nil
+}
+
+// Converts the value of the variable (decoded from JSON) to the value of the argument
+func variableValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string, bool, int:
+		return v, nil
+	case float64:
+		// Integers that float64 represents exactly
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int(v), nil
+		}
+
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("must be number, string or bool, but it's %T", value)
+}
+
+// Returns a copy of arguments where variables are replaced with their converted values, arguments of null variables are removed
+func substituteVariables(arguments map[string]interface{}, variables map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(arguments))
+
+	for name, value := range arguments {
+		if !isVariable(value) {
+			result[name] = value
+			continue
+		}
+
+		if variable := variables[string(value.(Variable))]; variable != nil {
+			result[name] = variable
+		}
+	}
+
+	return result
+}
+
+// Value of the argument written as a variable ("$name" without quotes), keeps the name without "$".
+// Quoted values that start with "$" are strings
+type Variable string
+
+// Checks if the value of the argument is a variable ("$name")
+func isVariable(value interface{}) bool {
+	_, ok := value.(Variable)
+	return ok
+}
@@ -0,0 +1,65 @@
+package hypeql
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestApplyVariables(t *testing.T) {
+	parser := NewQueryParser(QueryParserConfig{})
+
+	body, err := parser.Parse(`{version,goods(category: $category, page: $page, limit: $limit, note: "text"){name}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	applied, err := ApplyVariables(body, map[string]any{
+		"category": "food",
+		"page":     float64(2),
+		"limit":    nil,
+	})
+	if err != nil {
+		t.Fatal("Variables error: " + err.Error())
+	}
+
+	if fmt.Sprint(applied) != fmt.Sprint([]any{"version", []any{"goods", []any{"name"}, map[string]any{"category": "food", "page": 2, "note": "text"}}}) {
+		t.Fatal("Not equal: ", applied)
+	}
+
+	// Parsed body isn't changed
+	if body[1].([]any)[2].(map[string]any)["category"] != Variable("category") {
+		t.Fatal("Not equal")
+	}
+
+	resp, err := NewResponseGenerator(ResponseGeneratorConfig{}).Generate(applied[1:], Store{}, map[string]any{})
+	if err != nil {
+		t.Fatal("Process error: " + err.Error())
+	}
+
+	if resp != `{"goods":[{"name":"food 2 nil 99.5 true false"}]}` {
+		t.Fatal("Not equal: " + resp)
+	}
+
+	if _, err := ApplyVariables(body, map[string]any{"category": "food"}); err == nil || err.Error() != "variable $limit is not defined" {
+		t.Fatal("Not equal: ", err)
+	}
+
+	if _, err := ApplyVariables(body, map[string]any{"category": []any{}, "page": 1, "limit": 1}); err == nil {
+		t.Fatal("Not equal")
+	}
+
+	// Quoted values that start with "$" aren't variables
+	body, err = parser.Parse(`{goods(category: "$5", page: $page){name}}`)
+	if err != nil {
+		t.Fatal("Parse error: " + err.Error())
+	}
+
+	applied, err = ApplyVariables(body, map[string]any{"page": 1})
+	if err != nil {
+		t.Fatal("Variables error: " + err.Error())
+	}
+
+	if fmt.Sprint(applied) != fmt.Sprint([]any{[]any{"goods", []any{"name"}, map[string]any{"category": "$5", "page": 1}}}) {
+		t.Fatal("Not equal: ", applied)
+	}
+}